@@ -0,0 +1,148 @@
+package jsonflex
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+)
+
+// AsCount returns a Converter that reports the number of elements in an array.
+// Every element is still validated with valueConv, so the count is only
+// returned if the whole array converts successfully.
+func AsCount[T any](valueConv Converter[T]) Converter[int] {
+	return func(v any) (int, error) {
+		items, err := AsArray(valueConv)(v)
+		if err != nil {
+			return 0, err
+		}
+		return len(items), nil
+	}
+}
+
+// AsSum returns a Converter that sums a numeric array.
+// Each element is converted with AsFloat64, and the first non-numeric element
+// aborts the conversion with its index in the error.
+func AsSum() Converter[float64] {
+	return func(v any) (float64, error) {
+		items, err := AsArray(AsFloat64())(v)
+		if err != nil {
+			return 0, err
+		}
+		var sum float64
+		for _, item := range items {
+			sum += item
+		}
+		return sum, nil
+	}
+}
+
+// AsHistogram returns a Converter that counts the values of a numeric array
+// into the len(bounds)+1 buckets delimited by bounds, which must be sorted in
+// ascending order. Bucket 0 counts values below bounds[0], bucket i counts
+// values in [bounds[i-1], bounds[i]), and the last bucket counts values at or
+// above the last bound. NaN elements are rejected with their index.
+func AsHistogram(bounds []float64) Converter[[]int] {
+	return func(v any) ([]int, error) {
+		if !slices.IsSorted(bounds) {
+			return nil, errors.New("AsHistogram: bounds must be sorted in ascending order")
+		}
+		items, err := AsArray(AsFloat64())(v)
+		if err != nil {
+			return nil, err
+		}
+		counts := make([]int, len(bounds)+1)
+		for i, item := range items {
+			if math.IsNaN(item) {
+				ce := newConversionError(item, "histogram value")
+				ce.Reason = "value is NaN"
+				return nil, itemError(i, item, ce)
+			}
+			counts[sort.Search(len(bounds), func(j int) bool { return bounds[j] > item })]++
+		}
+		return counts, nil
+	}
+}
+
+// AsDeltas returns a Converter that converts a numeric array and returns the
+// differences between adjacent elements, so [1, 4, 2] yields [3, -2].
+// Arrays with fewer than two elements yield an empty slice.
+func AsDeltas() Converter[[]float64] {
+	return func(v any) ([]float64, error) {
+		items, err := AsArray(AsFloat64())(v)
+		if err != nil {
+			return nil, err
+		}
+		deltas := make([]float64, 0, max(len(items)-1, 0))
+		for i := 1; i < len(items); i++ {
+			deltas = append(deltas, items[i]-items[i-1])
+		}
+		return deltas, nil
+	}
+}
+
+// AsPercentiles returns a Converter that converts a numeric array and computes
+// each of ps, given in [0, 100], over a sorted copy of it. Percentiles between
+// two elements are linearly interpolated, so the 50th percentile of [1, 2, 3, 4]
+// is 2.5. Empty arrays and NaN elements are rejected.
+func AsPercentiles(ps ...float64) Converter[map[float64]float64] {
+	return func(v any) (map[float64]float64, error) {
+		for _, p := range ps {
+			if !(p >= 0 && p <= 100) {
+				return nil, fmt.Errorf("AsPercentiles: percentiles must be in [0, 100], got %v", p)
+			}
+		}
+		items, err := AsArray(AsFloat64())(v)
+		if err != nil {
+			return nil, err
+		}
+		if len(items) == 0 {
+			ce := newConversionError(v, "percentiles")
+			ce.Reason = "array is empty"
+			return nil, ce
+		}
+		for i, item := range items {
+			if math.IsNaN(item) {
+				ce := newConversionError(item, "percentile value")
+				ce.Reason = "value is NaN"
+				return nil, itemError(i, item, ce)
+			}
+		}
+		slices.Sort(items)
+		result := make(map[float64]float64, len(ps))
+		for _, p := range ps {
+			rank := p / 100 * float64(len(items)-1)
+			lo := int(math.Floor(rank))
+			hi := min(lo+1, len(items)-1)
+			result[p] = items[lo] + (rank-float64(lo))*(items[hi]-items[lo])
+		}
+		return result, nil
+	}
+}
+
+// AsEWMA returns a Converter that converts a numeric array and returns its
+// exponentially weighted moving average with smoothing factor alpha, which
+// must be in (0, 1]. The first output equals the first input, and each later
+// output is alpha*x[i] + (1-alpha)*out[i-1]; an alpha of 1 returns the input
+// unchanged.
+func AsEWMA(alpha float64) Converter[[]float64] {
+	return func(v any) ([]float64, error) {
+		if !(alpha > 0 && alpha <= 1) {
+			return nil, fmt.Errorf("AsEWMA: alpha must be in (0, 1], got %v", alpha)
+		}
+		items, err := AsArray(AsFloat64())(v)
+		if err != nil {
+			return nil, err
+		}
+		smoothed := make([]float64, len(items))
+		for i, item := range items {
+			if i == 0 {
+				smoothed[i] = item
+				continue
+			}
+			smoothed[i] = alpha*item + (1-alpha)*smoothed[i-1]
+		}
+		return smoothed, nil
+	}
+}
@@ -0,0 +1,167 @@
+package jsonflex_test
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/krelinga/go-jsonflex"
+)
+
+func TestAsCountAndSum(t *testing.T) {
+	values := jsonflex.Array{jsonflex.Number(1), jsonflex.Number(2.5), jsonflex.Number(-0.5)}
+
+	count, err := jsonflex.AsCount(jsonflex.AsFloat64())(values)
+	if err != nil || count != 3 {
+		t.Errorf("expected count 3, got %d with error %v", count, err)
+	}
+	sum, err := jsonflex.AsSum()(values)
+	if err != nil || sum != 3 {
+		t.Errorf("expected sum 3, got %v with error %v", sum, err)
+	}
+
+	bad := jsonflex.Array{jsonflex.Number(1), "two"}
+	if _, err := jsonflex.AsCount(jsonflex.AsFloat64())(bad); !errors.Is(err, jsonflex.ErrCannotConvert) {
+		t.Errorf("expected conversion error from AsCount, got %v", err)
+	}
+	_, err = jsonflex.AsSum()(bad)
+	if !errors.Is(err, jsonflex.ErrCannotConvert) || !strings.Contains(err.Error(), "item 1") {
+		t.Errorf("expected conversion error naming item 1 from AsSum, got %v", err)
+	}
+}
+
+func TestAsHistogram(t *testing.T) {
+	values := jsonflex.Array{
+		jsonflex.Number(-5), jsonflex.Number(0), jsonflex.Number(5), jsonflex.Number(9.99),
+		jsonflex.Number(10), jsonflex.Number(15), jsonflex.Number(20), jsonflex.Number(1e9),
+	}
+	got, err := jsonflex.AsHistogram([]float64{0, 10, 20})(values)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if diff := cmp.Diff([]int{1, 3, 2, 2}, got); diff != "" {
+		t.Errorf("mismatch (-want +got):\n%s", diff)
+	}
+
+	got, err = jsonflex.AsHistogram(nil)(values)
+	if err != nil || len(got) != 1 || got[0] != len(values) {
+		t.Errorf("expected a single bucket counting everything, got %v with error %v", got, err)
+	}
+
+	if _, err := jsonflex.AsHistogram([]float64{10, 0})(values); err == nil || !strings.Contains(err.Error(), "sorted") {
+		t.Errorf("expected error for unsorted bounds, got %v", err)
+	}
+	_, err = jsonflex.AsHistogram([]float64{0})(jsonflex.Array{jsonflex.Number(1), math.NaN()})
+	if !errors.Is(err, jsonflex.ErrCannotConvert) || !strings.Contains(err.Error(), "item 1") {
+		t.Errorf("expected NaN error naming item 1, got %v", err)
+	}
+	if _, err := jsonflex.AsHistogram([]float64{0})(jsonflex.Array{"x"}); !errors.Is(err, jsonflex.ErrCannotConvert) {
+		t.Errorf("expected conversion error, got %v", err)
+	}
+}
+
+func TestAsDeltas(t *testing.T) {
+	cases := []struct {
+		name     string
+		input    jsonflex.Array
+		expected []float64
+	}{
+		{name: "empty", input: jsonflex.Array{}, expected: []float64{}},
+		{name: "single", input: jsonflex.Array{jsonflex.Number(5)}, expected: []float64{}},
+		{
+			name:     "mixed",
+			input:    jsonflex.Array{jsonflex.Number(1), jsonflex.Number(4), jsonflex.Number(2), jsonflex.Number(2), jsonflex.Number(10)},
+			expected: []float64{3, -2, 0, 8},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got, err := jsonflex.AsDeltas()(c.input)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(c.expected, got); diff != "" {
+				t.Errorf("mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+
+	_, err := jsonflex.AsDeltas()(jsonflex.Array{jsonflex.Number(1), "2"})
+	if !errors.Is(err, jsonflex.ErrCannotConvert) || !strings.Contains(err.Error(), "item 1") {
+		t.Errorf("expected conversion error naming item 1, got %v", err)
+	}
+}
+
+func TestAsPercentiles(t *testing.T) {
+	// 1 through 100, shuffled so that sorting is exercised.
+	input := make(jsonflex.Array, 0, 100)
+	for i := range 100 {
+		input = append(input, jsonflex.Number((i*37)%100+1))
+	}
+	got, err := jsonflex.AsPercentiles(0, 50, 90, 99, 100)(input)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := map[float64]float64{0: 1, 50: 50.5, 90: 90.1, 99: 99.01, 100: 100}
+	if len(got) != len(expected) {
+		t.Fatalf("expected %d percentiles, got %v", len(expected), got)
+	}
+	for p, want := range expected {
+		if math.Abs(got[p]-want) > 1e-9 {
+			t.Errorf("p%v: expected %v, got %v", p, want, got[p])
+		}
+	}
+
+	single, err := jsonflex.AsPercentiles(25)(jsonflex.Array{jsonflex.Number(7)})
+	if err != nil || single[25] != 7 {
+		t.Errorf("expected 7 for a single element, got %v with error %v", single, err)
+	}
+	if _, err := jsonflex.AsPercentiles(101)(input); err == nil {
+		t.Error("expected error for percentile above 100")
+	}
+	if _, err := jsonflex.AsPercentiles(-1)(input); err == nil {
+		t.Error("expected error for negative percentile")
+	}
+	if _, err := jsonflex.AsPercentiles(50)(jsonflex.Array{}); !errors.Is(err, jsonflex.ErrCannotConvert) {
+		t.Errorf("expected conversion error for empty array, got %v", err)
+	}
+	_, err = jsonflex.AsPercentiles(50)(jsonflex.Array{jsonflex.Number(1), "2"})
+	if !errors.Is(err, jsonflex.ErrCannotConvert) || !strings.Contains(err.Error(), "item 1") {
+		t.Errorf("expected conversion error naming item 1, got %v", err)
+	}
+}
+
+func TestAsEWMA(t *testing.T) {
+	input := jsonflex.Array{jsonflex.Number(10), jsonflex.Number(20), jsonflex.Number(0), jsonflex.Number(10)}
+	got, err := jsonflex.AsEWMA(0.5)(input)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if diff := cmp.Diff([]float64{10, 15, 7.5, 8.75}, got); diff != "" {
+		t.Errorf("mismatch (-want +got):\n%s", diff)
+	}
+
+	got, err = jsonflex.AsEWMA(1)(input)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if diff := cmp.Diff([]float64{10, 20, 0, 10}, got); diff != "" {
+		t.Errorf("mismatch (-want +got):\n%s", diff)
+	}
+
+	got, err = jsonflex.AsEWMA(0.5)(jsonflex.Array{})
+	if err != nil || len(got) != 0 {
+		t.Errorf("expected empty result, got %v with error %v", got, err)
+	}
+	for _, alpha := range []float64{0, -0.5, 1.5, math.NaN()} {
+		if _, err := jsonflex.AsEWMA(alpha)(input); err == nil {
+			t.Errorf("expected error for alpha %v", alpha)
+		}
+	}
+	_, err = jsonflex.AsEWMA(0.5)(jsonflex.Array{jsonflex.Number(1), "2"})
+	if !errors.Is(err, jsonflex.ErrCannotConvert) || !strings.Contains(err.Error(), "item 1") {
+		t.Errorf("expected conversion error naming item 1, got %v", err)
+	}
+}
@@ -0,0 +1,837 @@
+package jsonflex
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// DuplicatePolicy controls how converters that build maps from arrays handle
+// two elements that produce the same key.
+type DuplicatePolicy int
+
+const (
+	// DuplicateError causes the conversion to fail with ErrDuplicateKey.
+	DuplicateError DuplicatePolicy = iota
+	// DuplicateKeepFirst keeps the entry from the earliest element.
+	DuplicateKeepFirst
+	// DuplicateKeepLast keeps the entry from the latest element.
+	DuplicateKeepLast
+)
+
+// AsIndexMap returns a Converter that converts each element of an array using
+// valueConv and maps the converted element to its position in the array.
+// Duplicate elements cause the conversion to fail with ErrDuplicateKey.
+// This is useful for diffing ordered lists of IDs.
+func AsIndexMap[K comparable](valueConv Converter[K]) Converter[map[K]int] {
+	return AsIndexMapWith(valueConv, DuplicateError)
+}
+
+// AsIndexMapWith is like AsIndexMap, but uses dup to decide how duplicate
+// elements are handled.
+func AsIndexMapWith[K comparable](valueConv Converter[K], dup DuplicatePolicy) Converter[map[K]int] {
+	return func(v any) (map[K]int, error) {
+		keys, err := AsArray(valueConv)(v)
+		if err != nil {
+			return nil, err
+		}
+		result := make(map[K]int, len(keys))
+		for i, key := range keys {
+			if first, exists := result[key]; exists {
+				switch dup {
+				case DuplicateKeepFirst:
+					continue
+				case DuplicateKeepLast:
+				default:
+					return nil, fmt.Errorf("item %d: %w %v (first seen at item %d)", i, ErrDuplicateKey, key, first)
+				}
+			}
+			result[key] = i
+		}
+		return result, nil
+	}
+}
+
+// Flatten returns a Converter for arrays whose elements are themselves arrays.
+// Each element is converted with valueConv and the results are concatenated
+// into a single slice, so empty inner arrays contribute nothing.
+// Conversion errors identify the index of the offending outer element.
+func Flatten[T any](valueConv Converter[[]T]) Converter[[]T] {
+	return func(v any) ([]T, error) {
+		chunks, err := AsArray(valueConv)(v)
+		if err != nil {
+			return nil, err
+		}
+		var result []T
+		for _, chunk := range chunks {
+			result = append(result, chunk...)
+		}
+		return result, nil
+	}
+}
+
+// AsArrayWindow returns a Converter that converts every element of an array
+// using valueConv and then yields each contiguous window of size elements,
+// i.e. n-size+1 windows for an array of length n.
+// Arrays shorter than size produce an empty result rather than an error.
+// The windows share backing storage, so callers should copy a window before
+// modifying it.
+func AsArrayWindow[T any](size int, valueConv Converter[T]) Converter[[][]T] {
+	return func(v any) ([][]T, error) {
+		if size < 1 {
+			return nil, fmt.Errorf("invalid window size %d", size)
+		}
+		items, err := AsArray(valueConv)(v)
+		if err != nil {
+			return nil, err
+		}
+		if len(items) < size {
+			return [][]T{}, nil
+		}
+		result := make([][]T, 0, len(items)-size+1)
+		for i := 0; i+size <= len(items); i++ {
+			result = append(result, items[i:i+size:i+size])
+		}
+		return result, nil
+	}
+}
+
+// AsArrayDiscriminated returns a Converter for heterogeneous arrays of objects
+// whose concrete shape is named by a string field such as "type".
+// For each element it reads the discriminator at key and applies the matching
+// Converter from cases. An element with an unknown discriminator aborts the
+// conversion with the element index and discriminator in the error.
+func AsArrayDiscriminated[T any](key string, cases map[string]Converter[T]) Converter[[]T] {
+	return asArrayDiscriminated(key, cases, false)
+}
+
+// AsArrayDiscriminatedSkipUnknown is like AsArrayDiscriminated, but silently
+// drops elements whose discriminator has no entry in cases.
+func AsArrayDiscriminatedSkipUnknown[T any](key string, cases map[string]Converter[T]) Converter[[]T] {
+	return asArrayDiscriminated(key, cases, true)
+}
+
+func asArrayDiscriminated[T any](key string, cases map[string]Converter[T], skipUnknown bool) Converter[[]T] {
+	return func(v any) ([]T, error) {
+		objs, err := AsArray(AsObject[Object]())(v)
+		if err != nil {
+			return nil, err
+		}
+		result := make([]T, 0, len(objs))
+		for i, obj := range objs {
+			disc, err := GetField(obj, key, AsString())
+			if err != nil {
+				return nil, fmt.Errorf("item %d: %w", i, err)
+			}
+			conv, ok := cases[disc]
+			if !ok {
+				if skipUnknown {
+					continue
+				}
+				return nil, fmt.Errorf("item %d: %w: unknown %s %q", i, ErrCannotConvert, key, disc)
+			}
+			converted, err := conv(obj)
+			if err != nil {
+				return nil, fmt.Errorf("item %d (%s %q): %w", i, key, disc, err)
+			}
+			result = append(result, converted)
+		}
+		return result, nil
+	}
+}
+
+// ForEachInArray converts each element of arr using valueConv and immediately
+// passes it to fn, without materializing the converted slice.
+// It stops at the first error from either the conversion or fn.
+// This is the streaming counterpart to FromArray for very large arrays.
+func ForEachInArray[T any](arr Array, valueConv Converter[T], fn func(i int, v T) error) error {
+	for i, item := range arr {
+		converted, err := valueConv(item)
+		if err != nil {
+			return itemError(i, item, err)
+		}
+		if err := fn(i, converted); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// StreamArray converts the elements of arr in a new goroutine and delivers
+// them, in order, on the returned value channel.
+//
+// The goroutine stops at the first conversion error, which is sent on the
+// error channel, or when ctx is cancelled, in which case ctx.Err() is sent.
+// Both channels are closed when the goroutine exits, so callers can range
+// over the value channel and then receive from the error channel; a nil
+// receive means every element was delivered. The error channel is buffered,
+// so the goroutine never blocks on it, but callers must keep receiving
+// values or cancel ctx to let the goroutine finish.
+func StreamArray[T any](ctx context.Context, arr Array, valueConv Converter[T]) (<-chan T, <-chan error) {
+	values := make(chan T)
+	errs := make(chan error, 1)
+	go func() {
+		defer close(errs)
+		defer close(values)
+		for i, item := range arr {
+			converted, err := valueConv(item)
+			if err != nil {
+				errs <- itemError(i, item, err)
+				return
+			}
+			select {
+			case values <- converted:
+			case <-ctx.Done():
+				errs <- ctx.Err()
+				return
+			}
+		}
+	}()
+	return values, errs
+}
+
+// AsArrayReversed returns a Converter that converts an array like AsArray and
+// returns the elements in reverse order.
+// Conversion errors report the element's original (pre-reversal) index.
+func AsArrayReversed[T any](valueConv Converter[T]) Converter[[]T] {
+	return func(v any) ([]T, error) {
+		items, err := AsArray(valueConv)(v)
+		if err != nil {
+			return nil, err
+		}
+		slices.Reverse(items)
+		return items, nil
+	}
+}
+
+// AsArrayGroupBy returns a Converter that converts each element of an array
+// using valueConv and buckets it under the key computed by keyFn.
+// Elements within each group keep their input order.
+// Conversion errors abort with the element index in the error.
+func AsArrayGroupBy[K comparable, V any](valueConv Converter[V], keyFn func(V) K) Converter[map[K][]V] {
+	return func(v any) (map[K][]V, error) {
+		items, err := AsArray(valueConv)(v)
+		if err != nil {
+			return nil, err
+		}
+		result := make(map[K][]V)
+		for _, item := range items {
+			key := keyFn(item)
+			result[key] = append(result[key], item)
+		}
+		return result, nil
+	}
+}
+
+// AsMultiMap is another name for AsArrayGroupBy, for readers who think of
+// the result as a map that allows several values per key.
+func AsMultiMap[K comparable, V any](valueConv Converter[V], keyFn func(V) K) Converter[map[K][]V] {
+	return AsArrayGroupBy(valueConv, keyFn)
+}
+
+// AsFirst returns a Converter that converts the elements of an array in order
+// and returns the first one for which pred returns true. A nil pred matches
+// the first element.
+// Elements after the match are not converted. If no element matches, the
+// error wraps ErrNotFound.
+func AsFirst[T any](valueConv Converter[T], pred func(T) bool) Converter[T] {
+	return func(v any) (T, error) {
+		var zero T
+		if v == nil {
+			return zero, ErrNullValue
+		}
+		arr, ok := v.([]any)
+		if !ok {
+			return zero, newConversionError(v, "Array")
+		}
+		for i, item := range arr {
+			converted, err := valueConv(item)
+			if err != nil {
+				return zero, itemError(i, item, err)
+			}
+			if pred == nil || pred(converted) {
+				return converted, nil
+			}
+		}
+		return zero, ErrNotFound
+	}
+}
+
+// AsMapFromEntries returns a Converter for maps encoded as arrays of
+// single-entry objects, such as [{"a": 1}, {"b": 2}].
+// Each element must be an object with exactly one key; its value is converted
+// with valueConv and the entries are merged into one map. Duplicate keys
+// across elements cause the conversion to fail with ErrDuplicateKey.
+func AsMapFromEntries[V any](valueConv Converter[V]) Converter[map[string]V] {
+	return AsMapFromEntriesWith(valueConv, DuplicateError)
+}
+
+// AsMapFromEntriesWith is like AsMapFromEntries, but uses dup to decide how
+// duplicate keys are handled.
+func AsMapFromEntriesWith[V any](valueConv Converter[V], dup DuplicatePolicy) Converter[map[string]V] {
+	return func(v any) (map[string]V, error) {
+		objs, err := AsArray(AsObject[Object]())(v)
+		if err != nil {
+			return nil, err
+		}
+		result := make(map[string]V, len(objs))
+		for i, obj := range objs {
+			key, value, err := singleEntry(obj)
+			if err != nil {
+				return nil, fmt.Errorf("item %d: %w", i, err)
+			}
+			if _, exists := result[key]; exists {
+				switch dup {
+				case DuplicateKeepFirst:
+					continue
+				case DuplicateKeepLast:
+				default:
+					return nil, fmt.Errorf("item %d: %w %q", i, ErrDuplicateKey, key)
+				}
+			}
+			converted, err := valueConv(value)
+			if err != nil {
+				return nil, fmt.Errorf("item %d: key %q: %w", i, key, err)
+			}
+			result[key] = converted
+		}
+		return result, nil
+	}
+}
+
+// IndexedValue pairs a converted array element with its position.
+type IndexedValue[T any] struct {
+	Index int
+	Value T
+}
+
+// AsEnumerated returns a Converter that converts each element of an array
+// using valueConv and pairs it with its index.
+// This is handy when rendering ordered lists that need positional metadata.
+func AsEnumerated[T any](valueConv Converter[T]) Converter[[]IndexedValue[T]] {
+	return func(v any) ([]IndexedValue[T], error) {
+		items, err := AsArray(valueConv)(v)
+		if err != nil {
+			return nil, err
+		}
+		result := make([]IndexedValue[T], len(items))
+		for i, item := range items {
+			result[i] = IndexedValue[T]{Index: i, Value: item}
+		}
+		return result, nil
+	}
+}
+
+// singleEntry returns the only key and value of obj, which must have exactly
+// one entry.
+func singleEntry(obj Object) (string, any, error) {
+	if len(obj) != 1 {
+		ce := newConversionError(obj, "entry")
+		ce.Reason = fmt.Sprintf("object has %d keys, want 1", len(obj))
+		return "", nil, ce
+	}
+	for key, value := range obj {
+		return key, value, nil
+	}
+	panic("unreachable")
+}
+
+// AsArrayMax is like AsArray, but rejects arrays with more than maxLen
+// elements with ErrTooManyElements.
+// The length check happens before anything is converted or allocated, so
+// oversized input from untrusted clients is cheap to reject.
+func AsArrayMax[T any](maxLen int, valueConv Converter[T]) Converter[[]T] {
+	return func(v any) ([]T, error) {
+		if arr, ok := v.([]any); ok && len(arr) > maxLen {
+			return nil, fmt.Errorf("%w: %d elements exceeds limit of %d", ErrTooManyElements, len(arr), maxLen)
+		}
+		return AsArray(valueConv)(v)
+	}
+}
+
+// Partition holds the result of AsPartition.
+type Partition[T any] struct {
+	// Matched holds the elements for which the predicate returned true.
+	Matched []T
+	// Rest holds all other elements.
+	Rest []T
+}
+
+// AsPartition returns a Converter that converts every element of an array
+// using valueConv and routes it into Matched or Rest depending on pred.
+// Both halves keep input order. Conversion errors abort with the element
+// index in the error.
+func AsPartition[T any](valueConv Converter[T], pred func(T) bool) Converter[Partition[T]] {
+	return func(v any) (Partition[T], error) {
+		items, err := AsArray(valueConv)(v)
+		if err != nil {
+			return Partition[T]{}, err
+		}
+		var result Partition[T]
+		for _, item := range items {
+			if pred(item) {
+				result.Matched = append(result.Matched, item)
+			} else {
+				result.Rest = append(result.Rest, item)
+			}
+		}
+		return result, nil
+	}
+}
+
+// AsArrayPtr returns a Converter that converts each element of an array using
+// valueConv and stores a pointer to each result, which suits downstream APIs
+// that want pointers to large elements.
+// JSON null elements become nil pointers without calling valueConv. Other
+// conversion errors abort with the element index in the error.
+func AsArrayPtr[T any](valueConv Converter[T]) Converter[[]*T] {
+	return AsArray(func(v any) (*T, error) {
+		if v == nil {
+			return nil, nil
+		}
+		converted, err := valueConv(v)
+		if err != nil {
+			return nil, err
+		}
+		return &converted, nil
+	})
+}
+
+// AsArrayIndexed returns a Converter that converts each element of an array
+// with the Converter returned by convFor for that element's index.
+// This generalizes AsArray to position-dependent schemas, such as arrays that
+// alternate between two shapes. If convFor returns nil for an index, the
+// conversion fails with an error naming that index.
+func AsArrayIndexed[T any](convFor func(i int) Converter[T]) Converter[[]T] {
+	return func(v any) ([]T, error) {
+		arr, err := rawArray(v)
+		if err != nil {
+			return nil, err
+		}
+		result := make([]T, len(arr))
+		for i, item := range arr {
+			conv := convFor(i)
+			if conv == nil {
+				return nil, fmt.Errorf("item %d: no converter for index", i)
+			}
+			converted, err := conv(item)
+			if err != nil {
+				return nil, itemError(i, item, err)
+			}
+			result[i] = converted
+		}
+		return result, nil
+	}
+}
+
+// AsArrayCollect is like AsArray, but instead of stopping at the first
+// element that fails to convert it converts every element and reports all
+// failures together, each with its index. This is useful for auditing feeds.
+// The error is an *ErrorList whose Field values are the element indices.
+func AsArrayCollect[T any](valueConv Converter[T]) Converter[[]T] {
+	return AsArrayCollectN(math.MaxInt, valueConv)
+}
+
+// AsArrayCollectN is like AsArrayCollect, but stores at most n element
+// errors. Further failures are only counted, and the final error notes how
+// many were omitted and the total number of failures, which keeps error
+// aggregation bounded on pathological inputs.
+func AsArrayCollectN[T any](n int, valueConv Converter[T]) Converter[[]T] {
+	return func(v any) ([]T, error) {
+		arr, err := rawArray(v)
+		if err != nil {
+			return nil, err
+		}
+		result := make([]T, len(arr))
+		var errs ErrorList
+		for i, item := range arr {
+			converted, err := valueConv(item)
+			if err != nil {
+				if len(errs.Errors) < n {
+					errs.add(strconv.Itoa(i), itemError(i, item, err))
+				} else {
+					errs.Omitted++
+				}
+				continue
+			}
+			result[i] = converted
+		}
+		if err := errs.orNil(); err != nil {
+			return nil, err
+		}
+		return result, nil
+	}
+}
+
+// AsArrayCompact is like AsArray, but skips JSON null elements entirely, so
+// the result has no gaps. Non-null elements that fail conversion still abort
+// with their original index in the error.
+func AsArrayCompact[T any](valueConv Converter[T]) Converter[[]T] {
+	return func(v any) ([]T, error) {
+		arr, err := rawArray(v)
+		if err != nil {
+			return nil, err
+		}
+		result := make([]T, 0, len(arr))
+		for i, item := range arr {
+			if item == nil {
+				continue
+			}
+			converted, err := valueConv(item)
+			if err != nil {
+				return nil, itemError(i, item, err)
+			}
+			result = append(result, converted)
+		}
+		return result, nil
+	}
+}
+
+// AsArray2D returns a Converter that converts an array of arrays, converting
+// every inner element using valueConv.
+// Rows may have different lengths. Errors identify the failing element as
+// [row][col], or just the row if it is not an array.
+func AsArray2D[T any](valueConv Converter[T]) Converter[[][]T] {
+	return func(v any) ([][]T, error) {
+		rows, err := rawArray(v)
+		if err != nil {
+			return nil, err
+		}
+		result := make([][]T, len(rows))
+		for r, row := range rows {
+			cells, err := rawArray(row)
+			if err != nil {
+				return nil, fmt.Errorf("row %d: %w", r, err)
+			}
+			result[r] = make([]T, len(cells))
+			for c, cell := range cells {
+				converted, err := valueConv(cell)
+				if err != nil {
+					return nil, fmt.Errorf("item [%d][%d]: %w (value: %s)", r, c, err, truncate(formatValue(cell), 40))
+				}
+				result[r][c] = converted
+			}
+		}
+		return result, nil
+	}
+}
+
+// AsMatrix returns a Converter that converts an array of numeric arrays into
+// a [][]float64. Rows may have different lengths; use AsRectMatrix to require
+// equal lengths.
+func AsMatrix() Converter[[][]float64] {
+	return AsArray2D(AsFloat64())
+}
+
+// AsRectMatrix is like AsMatrix, but rejects ragged input whose rows do not
+// all have the same length as the first row.
+func AsRectMatrix() Converter[[][]float64] {
+	return func(v any) ([][]float64, error) {
+		m, err := AsMatrix()(v)
+		if err != nil {
+			return nil, err
+		}
+		for r, row := range m {
+			if len(row) != len(m[0]) {
+				ce := newConversionError(v, "rectangular matrix")
+				ce.Reason = fmt.Sprintf("row %d has %d columns, expected %d", r, len(row), len(m[0]))
+				return nil, ce
+			}
+		}
+		return m, nil
+	}
+}
+
+// AsArrayHead returns a Converter that converts only the first n elements of
+// an array using valueConv, or every element if there are fewer than n.
+// The remaining elements are never converted. A non-positive n yields an
+// empty slice.
+func AsArrayHead[T any](n int, valueConv Converter[T]) Converter[[]T] {
+	return func(v any) ([]T, error) {
+		arr, err := rawArray(v)
+		if err != nil {
+			return nil, err
+		}
+		return convertRange(arr, 0, min(max(n, 0), len(arr)), valueConv)
+	}
+}
+
+// AsArrayTail is like AsArrayHead, but keeps the last n elements, which suits
+// "most recent" views of large feeds. Errors report the element's index in
+// the original array.
+func AsArrayTail[T any](n int, valueConv Converter[T]) Converter[[]T] {
+	return func(v any) ([]T, error) {
+		arr, err := rawArray(v)
+		if err != nil {
+			return nil, err
+		}
+		return convertRange(arr, len(arr)-min(max(n, 0), len(arr)), len(arr), valueConv)
+	}
+}
+
+// rawArray returns v as an Array without copying it, with the same errors
+// as AsArray for null and non-array values. Converters that read elements in
+// place use it instead of AsArray(AsAny()), which copies the whole array.
+func rawArray(v any) (Array, error) {
+	if v == nil {
+		return nil, ErrNullValue
+	}
+	arr, ok := v.([]any)
+	if !ok {
+		return nil, newConversionError(v, "Array")
+	}
+	return arr, nil
+}
+
+// convertRange converts arr[start:end] using valueConv.
+func convertRange[T any](arr []any, start, end int, valueConv Converter[T]) ([]T, error) {
+	result := make([]T, 0, end-start)
+	for i := start; i < end; i++ {
+		converted, err := valueConv(arr[i])
+		if err != nil {
+			return nil, itemError(i, arr[i], err)
+		}
+		result = append(result, converted)
+	}
+	return result, nil
+}
+
+// AsArrayPairs returns a Converter that converts every element of an array
+// using valueConv and groups the results two at a time, turning
+// [x1, y1, x2, y2] into [[x1, y1], [x2, y2]].
+// Arrays of odd length are rejected with ErrCannotConvert.
+func AsArrayPairs[T any](valueConv Converter[T]) Converter[[][2]T] {
+	return func(v any) ([][2]T, error) {
+		arr, err := rawArray(v)
+		if err != nil {
+			return nil, err
+		}
+		if len(arr)%2 != 0 {
+			ce := newConversionError(v, "array of pairs")
+			ce.Reason = fmt.Sprintf("odd length %d", len(arr))
+			return nil, ce
+		}
+		items, err := AsArray(valueConv)(arr)
+		if err != nil {
+			return nil, err
+		}
+		result := make([][2]T, len(items)/2)
+		for i := range result {
+			result[i] = [2]T{items[2*i], items[2*i+1]}
+		}
+		return result, nil
+	}
+}
+
+// AsEnumArray returns a Converter that converts an array of strings to a
+// []T, where every element must be one of allowed.
+// Like AsArrayCollect, it reports every invalid element at once, each with
+// its index and value, instead of stopping at the first.
+func AsEnumArray[T ~string](allowed ...T) Converter[[]T] {
+	return AsArrayCollect(AsEnum(allowed...))
+}
+
+// AsArrayLookup returns a Converter that converts each element of an array
+// using valueConv and indexes the results by keyFn, folded to lower case with
+// strings.ToLower, for case-insensitive lookup by name. Callers must fold
+// their lookup keys the same way.
+// Keys that collide after folding fail with ErrDuplicateKey; use
+// AsArrayLookupWith to choose a different DuplicatePolicy.
+func AsArrayLookup[T any](valueConv Converter[T], keyFn func(T) string) Converter[map[string]T] {
+	return AsArrayLookupWith(valueConv, keyFn, DuplicateError)
+}
+
+// AsArrayLookupWith is like AsArrayLookup, but handles colliding keys
+// according to dup.
+func AsArrayLookupWith[T any](valueConv Converter[T], keyFn func(T) string, dup DuplicatePolicy) Converter[map[string]T] {
+	return func(v any) (map[string]T, error) {
+		items, err := AsArray(valueConv)(v)
+		if err != nil {
+			return nil, err
+		}
+		result := make(map[string]T, len(items))
+		first := make(map[string]int, len(items))
+		for i, item := range items {
+			key := strings.ToLower(keyFn(item))
+			if seen, exists := first[key]; exists {
+				switch dup {
+				case DuplicateKeepFirst:
+					continue
+				case DuplicateKeepLast:
+				default:
+					return nil, fmt.Errorf("item %d: %w %q (first seen at item %d)", i, ErrDuplicateKey, key, seen)
+				}
+			} else {
+				first[key] = i
+			}
+			result[key] = item
+		}
+		return result, nil
+	}
+}
+
+// AsRepeated returns a Converter for loosely typed "scalar or array" fields,
+// as found when bridging JSON to protobuf-style repeated fields:
+//   - JSON null becomes an empty slice.
+//   - An array is converted element by element, as by AsArray.
+//   - Any other value is converted with valueConv and wrapped in a
+//     one-element slice.
+//
+// A Converter only sees values that exist, so an absent field is still
+// reported as ErrFieldNotFound by GetField. Use GetFieldFallback with a
+// fallback that yields nil to treat absence as an empty slice too.
+func AsRepeated[T any](valueConv Converter[T]) Converter[[]T] {
+	return func(v any) ([]T, error) {
+		switch v.(type) {
+		case nil:
+			return []T{}, nil
+		case []any:
+			return AsArray(valueConv)(v)
+		}
+		item, err := valueConv(v)
+		if err != nil {
+			return nil, err
+		}
+		return []T{item}, nil
+	}
+}
+
+// Deinterleaved holds the result of AsDeinterleave.
+type Deinterleaved[T any] struct {
+	// Even holds the elements at indices 0, 2, 4, ...
+	Even []T
+	// Odd holds the elements at indices 1, 3, 5, ...
+	Odd []T
+}
+
+// AsDeinterleave returns a Converter that converts every element of an array
+// using valueConv and splits an interleaved [a0, b0, a1, b1, ...] layout into
+// its two streams. Arrays of odd length are accepted; the extra last element
+// lands in Even, so Even may be one longer than Odd.
+func AsDeinterleave[T any](valueConv Converter[T]) Converter[Deinterleaved[T]] {
+	return func(v any) (Deinterleaved[T], error) {
+		items, err := AsArray(valueConv)(v)
+		if err != nil {
+			return Deinterleaved[T]{}, err
+		}
+		result := Deinterleaved[T]{
+			Even: make([]T, 0, (len(items)+1)/2),
+			Odd:  make([]T, 0, len(items)/2),
+		}
+		for i, item := range items {
+			if i%2 == 0 {
+				result.Even = append(result.Even, item)
+			} else {
+				result.Odd = append(result.Odd, item)
+			}
+		}
+		return result, nil
+	}
+}
+
+// AsArrayStride returns a Converter that keeps every step-th element of an
+// array, starting with the first, and converts only those with valueConv.
+// The whole input array is still read, since it is already decoded, but the
+// skipped elements are never converted. A step below 1 is an error.
+func AsArrayStride[T any](step int, valueConv Converter[T]) Converter[[]T] {
+	return func(v any) ([]T, error) {
+		if step < 1 {
+			return nil, fmt.Errorf("AsArrayStride: step must be at least 1, got %d", step)
+		}
+		arr, err := rawArray(v)
+		if err != nil {
+			return nil, err
+		}
+		result := make([]T, 0, (len(arr)+step-1)/step)
+		for i := 0; i < len(arr); i += step {
+			converted, err := valueConv(arr[i])
+			if err != nil {
+				return nil, itemError(i, arr[i], err)
+			}
+			result = append(result, converted)
+		}
+		return result, nil
+	}
+}
+
+// AsArraySample returns a Converter that keeps a pseudo-random subset of n
+// elements of an array, in their original order, and converts only those
+// with valueConv. The subset depends only on seed and the array length, so
+// the same input always yields the same sample. If the array has n elements
+// or fewer, all of them are kept; a non-positive n keeps none.
+func AsArraySample[T any](n int, seed int64, valueConv Converter[T]) Converter[[]T] {
+	return func(v any) ([]T, error) {
+		arr, err := rawArray(v)
+		if err != nil {
+			return nil, err
+		}
+		need := min(max(n, 0), len(arr))
+		rng := rand.New(rand.NewPCG(uint64(seed), 0))
+		result := make([]T, 0, need)
+		// Selection sampling: keep each element with probability
+		// need/remaining, which picks exactly need elements in order.
+		for i, item := range arr {
+			if need == 0 {
+				break
+			}
+			if rng.IntN(len(arr)-i) >= need {
+				continue
+			}
+			converted, err := valueConv(item)
+			if err != nil {
+				return nil, itemError(i, item, err)
+			}
+			result = append(result, converted)
+			need--
+		}
+		return result, nil
+	}
+}
+
+// Run is a value repeated Count times in a row, as produced by AsRLE.
+type Run[T any] struct {
+	Value T
+	Count int
+}
+
+// AsRLE returns a Converter that converts each element of an array using
+// valueConv and run-length encodes the results, collapsing consecutive equal
+// values into a single Run. Equal values that are not adjacent form separate
+// runs.
+func AsRLE[T comparable](valueConv Converter[T]) Converter[[]Run[T]] {
+	return func(v any) ([]Run[T], error) {
+		items, err := AsArray(valueConv)(v)
+		if err != nil {
+			return nil, err
+		}
+		runs := []Run[T]{}
+		for _, item := range items {
+			if n := len(runs); n > 0 && runs[n-1].Value == item {
+				runs[n-1].Count++
+				continue
+			}
+			runs = append(runs, Run[T]{Value: item, Count: 1})
+		}
+		return runs, nil
+	}
+}
+
+// AsSortedSet returns a Converter that converts each element of an array
+// using valueConv and returns the distinct results in ascending order, such
+// as a sorted set of IDs. Conversion errors abort with the element index in
+// the error.
+func AsSortedSet[T cmp.Ordered](valueConv Converter[T]) Converter[[]T] {
+	return func(v any) ([]T, error) {
+		items, err := AsArray(valueConv)(v)
+		if err != nil {
+			return nil, err
+		}
+		slices.Sort(items)
+		return slices.Compact(items), nil
+	}
+}
@@ -0,0 +1,844 @@
+package jsonflex_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/krelinga/go-jsonflex"
+)
+
+func TestAsIndexMap(t *testing.T) {
+	ids := jsonflex.Array{jsonflex.Number(28), jsonflex.Number(12), jsonflex.Number(878)}
+	got, err := jsonflex.AsIndexMap(jsonflex.AsInt32())(ids)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if diff := cmp.Diff(map[int32]int{28: 0, 12: 1, 878: 2}, got); diff != "" {
+		t.Errorf("mismatch (-want +got):\n%s", diff)
+	}
+
+	dupes := jsonflex.Array{"a", "b", "a"}
+	_, err = jsonflex.AsIndexMap(jsonflex.AsString())(dupes)
+	if !errors.Is(err, jsonflex.ErrDuplicateKey) {
+		t.Errorf("expected duplicate key error, got %v", err)
+	}
+
+	cases := []struct {
+		name     string
+		dup      jsonflex.DuplicatePolicy
+		expected map[string]int
+	}{
+		{name: "Keep First", dup: jsonflex.DuplicateKeepFirst, expected: map[string]int{"a": 0, "b": 1}},
+		{name: "Keep Last", dup: jsonflex.DuplicateKeepLast, expected: map[string]int{"a": 2, "b": 1}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got, err := jsonflex.AsIndexMapWith(jsonflex.AsString(), c.dup)(dupes)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(c.expected, got); diff != "" {
+				t.Errorf("mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestFlatten(t *testing.T) {
+	pages := jsonflex.Array{
+		jsonflex.Array{jsonflex.Object{"id": jsonflex.Number(1), "name": "Action"}},
+		jsonflex.Array{},
+		jsonflex.Array{
+			jsonflex.Object{"id": jsonflex.Number(2), "name": "Adventure"},
+			jsonflex.Object{"id": jsonflex.Number(3), "name": "Science Fiction"},
+		},
+	}
+	genres, err := jsonflex.Flatten(jsonflex.AsArray(jsonflex.AsObject[Genre]()))(pages)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(genres) != 3 || assertNoError(genres[0].ID())(t) != 1 || assertNoError(genres[2].Name())(t) != "Science Fiction" {
+		t.Errorf("unexpected flattened genres: %v", genres)
+	}
+
+	_, err = jsonflex.Flatten(jsonflex.AsArray(jsonflex.AsString()))(jsonflex.Array{jsonflex.Array{"a"}, "b"})
+	if !errors.Is(err, jsonflex.ErrCannotConvert) {
+		t.Errorf("expected conversion error, got %v", err)
+	}
+}
+
+func TestAsArrayWindow(t *testing.T) {
+	series := jsonflex.Array{jsonflex.Number(1), jsonflex.Number(2), jsonflex.Number(3), jsonflex.Number(4)}
+	got, err := jsonflex.AsArrayWindow(3, jsonflex.AsFloat64())(series)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if diff := cmp.Diff([][]float64{{1, 2, 3}, {2, 3, 4}}, got); diff != "" {
+		t.Errorf("mismatch (-want +got):\n%s", diff)
+	}
+
+	got, err = jsonflex.AsArrayWindow(5, jsonflex.AsFloat64())(series)
+	if err != nil || got == nil || len(got) != 0 {
+		t.Errorf("expected empty result for short array, got %v with error %v", got, err)
+	}
+
+	if _, err := jsonflex.AsArrayWindow(0, jsonflex.AsFloat64())(series); err == nil {
+		t.Error("expected error for non-positive window size")
+	}
+}
+
+func TestAsArrayDiscriminated(t *testing.T) {
+	describe := func(kind string) jsonflex.Converter[string] {
+		return func(v any) (string, error) {
+			name, err := jsonflex.GetField(v.(jsonflex.Object), "name", jsonflex.AsString())
+			return kind + ":" + name, err
+		}
+	}
+	cases := map[string]jsonflex.Converter[string]{
+		"like":    describe("like"),
+		"comment": describe("comment"),
+	}
+	feed := jsonflex.Array{
+		jsonflex.Object{"type": "like", "name": "alice"},
+		jsonflex.Object{"type": "share", "name": "bob"},
+		jsonflex.Object{"type": "comment", "name": "carol"},
+	}
+
+	_, err := jsonflex.AsArrayDiscriminated("type", cases)(feed)
+	if !errors.Is(err, jsonflex.ErrCannotConvert) {
+		t.Errorf("expected conversion error for unknown discriminator, got %v", err)
+	}
+
+	got, err := jsonflex.AsArrayDiscriminatedSkipUnknown("type", cases)(feed)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if diff := cmp.Diff([]string{"like:alice", "comment:carol"}, got); diff != "" {
+		t.Errorf("mismatch (-want +got):\n%s", diff)
+	}
+
+	_, err = jsonflex.AsArrayDiscriminated("type", cases)(jsonflex.Array{jsonflex.Object{"name": "dave"}})
+	if !errors.Is(err, jsonflex.ErrFieldNotFound) {
+		t.Errorf("expected field not found error for missing discriminator, got %v", err)
+	}
+}
+
+func TestForEachInArray(t *testing.T) {
+	arr := jsonflex.Array{jsonflex.Number(1), jsonflex.Number(2), jsonflex.Number(3)}
+	var sum int32
+	err := jsonflex.ForEachInArray(arr, jsonflex.AsInt32(), func(i int, v int32) error {
+		sum += v
+		return nil
+	})
+	if err != nil || sum != 6 {
+		t.Errorf("expected sum 6, got %d with error %v", sum, err)
+	}
+
+	stop := errors.New("stop")
+	var visited []int
+	err = jsonflex.ForEachInArray(arr, jsonflex.AsInt32(), func(i int, v int32) error {
+		visited = append(visited, i)
+		if i == 1 {
+			return stop
+		}
+		return nil
+	})
+	if !errors.Is(err, stop) || len(visited) != 2 {
+		t.Errorf("expected to stop after item 1, visited %v with error %v", visited, err)
+	}
+
+	visited = nil
+	err = jsonflex.ForEachInArray(jsonflex.Array{jsonflex.Number(1), "x", jsonflex.Number(3)}, jsonflex.AsInt32(), func(i int, v int32) error {
+		visited = append(visited, i)
+		return nil
+	})
+	if !errors.Is(err, jsonflex.ErrCannotConvert) || len(visited) != 1 {
+		t.Errorf("expected conversion error after item 0, visited %v with error %v", visited, err)
+	}
+}
+
+func TestStreamArray(t *testing.T) {
+	arr := jsonflex.Array{jsonflex.Number(1), jsonflex.Number(2), jsonflex.Number(3)}
+	values, errs := jsonflex.StreamArray(context.Background(), arr, jsonflex.AsInt32())
+	var got []int32
+	for v := range values {
+		got = append(got, v)
+	}
+	if err := <-errs; err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if diff := cmp.Diff([]int32{1, 2, 3}, got); diff != "" {
+		t.Errorf("mismatch (-want +got):\n%s", diff)
+	}
+
+	values, errs = jsonflex.StreamArray(context.Background(), jsonflex.Array{jsonflex.Number(1), "x"}, jsonflex.AsInt32())
+	got = nil
+	for v := range values {
+		got = append(got, v)
+	}
+	if err := <-errs; !errors.Is(err, jsonflex.ErrCannotConvert) || len(got) != 1 {
+		t.Errorf("expected one value and a conversion error, got %v with error %v", got, err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	values, errs = jsonflex.StreamArray(ctx, arr, jsonflex.AsInt32())
+	<-values
+	cancel()
+	for range values {
+	}
+	if err := <-errs; err != nil && !errors.Is(err, context.Canceled) {
+		t.Errorf("expected nil or cancellation error, got %v", err)
+	}
+}
+
+func TestAsArrayReversed(t *testing.T) {
+	got, err := jsonflex.AsArrayReversed(jsonflex.AsString())(jsonflex.Array{"a", "b", "c"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if diff := cmp.Diff([]string{"c", "b", "a"}, got); diff != "" {
+		t.Errorf("mismatch (-want +got):\n%s", diff)
+	}
+
+	_, err = jsonflex.AsArrayReversed(jsonflex.AsString())(jsonflex.Array{"a", jsonflex.Number(1), "c"})
+	if !errors.Is(err, jsonflex.ErrCannotConvert) || !strings.Contains(err.Error(), "item 1") {
+		t.Errorf("expected conversion error naming item 1, got %v", err)
+	}
+}
+
+func TestAsArrayGroupBy(t *testing.T) {
+	items := jsonflex.Array{
+		jsonflex.Object{"id": jsonflex.Number(1), "name": "Action"},
+		jsonflex.Object{"id": jsonflex.Number(2), "name": "Adventure"},
+		jsonflex.Object{"id": jsonflex.Number(3), "name": "Animation"},
+		jsonflex.Object{"id": jsonflex.Number(4), "name": "Drama"},
+	}
+	byInitial := jsonflex.AsArrayGroupBy(jsonflex.AsObject[Genre](), func(g Genre) string {
+		return assertNoError(g.Name())(t)[:1]
+	})
+	got, err := byInitial(items)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	ids := map[string][]int32{}
+	for key, genres := range got {
+		for _, g := range genres {
+			ids[key] = append(ids[key], assertNoError(g.ID())(t))
+		}
+	}
+	if diff := cmp.Diff(map[string][]int32{"A": {1, 2, 3}, "D": {4}}, ids); diff != "" {
+		t.Errorf("mismatch (-want +got):\n%s", diff)
+	}
+
+	_, err = byInitial(jsonflex.Array{jsonflex.Object{}, "x"})
+	if !errors.Is(err, jsonflex.ErrCannotConvert) || !strings.Contains(err.Error(), "item 1") {
+		t.Errorf("expected conversion error naming item 1, got %v", err)
+	}
+}
+
+func TestAsMultiMap(t *testing.T) {
+	byStatus := jsonflex.AsMultiMap(jsonflex.AsString(), func(s string) string {
+		status, _, _ := strings.Cut(s, ":")
+		return status
+	})
+	got, err := byStatus(jsonflex.Array{"open:1", "closed:2", "open:3", "open:4"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := map[string][]string{"open": {"open:1", "open:3", "open:4"}, "closed": {"closed:2"}}
+	if diff := cmp.Diff(expected, got); diff != "" {
+		t.Errorf("mismatch (-want +got):\n%s", diff)
+	}
+	_, err = byStatus(jsonflex.Array{"open:1", nil})
+	if !errors.Is(err, jsonflex.ErrNullValue) || !strings.Contains(err.Error(), "item 1") {
+		t.Errorf("expected null value error naming item 1, got %v", err)
+	}
+}
+
+func TestAsFirst(t *testing.T) {
+	// The trailing string would fail conversion, proving elements after the
+	// match are not converted.
+	arr := jsonflex.Array{jsonflex.Number(1), jsonflex.Number(4), jsonflex.Number(6), "x"}
+	even := func(v int32) bool { return v%2 == 0 }
+
+	got, err := jsonflex.AsFirst(jsonflex.AsInt32(), even)(arr)
+	if err != nil || got != 4 {
+		t.Errorf("expected 4, got %d with error %v", got, err)
+	}
+	got, err = jsonflex.AsFirst(jsonflex.AsInt32(), nil)(arr)
+	if err != nil || got != 1 {
+		t.Errorf("expected 1, got %d with error %v", got, err)
+	}
+	_, err = jsonflex.AsFirst(jsonflex.AsInt32(), even)(jsonflex.Array{jsonflex.Number(1), jsonflex.Number(3)})
+	if !errors.Is(err, jsonflex.ErrNotFound) {
+		t.Errorf("expected not found error, got %v", err)
+	}
+	_, err = jsonflex.AsFirst(jsonflex.AsInt32(), even)(jsonflex.Array{"x"})
+	if !errors.Is(err, jsonflex.ErrCannotConvert) {
+		t.Errorf("expected conversion error, got %v", err)
+	}
+}
+
+func TestAsMapFromEntries(t *testing.T) {
+	entries := jsonflex.Array{
+		jsonflex.Object{"a": jsonflex.Number(1)},
+		jsonflex.Object{"b": jsonflex.Number(2)},
+	}
+	got, err := jsonflex.AsMapFromEntries(jsonflex.AsInt32())(entries)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if diff := cmp.Diff(map[string]int32{"a": 1, "b": 2}, got); diff != "" {
+		t.Errorf("mismatch (-want +got):\n%s", diff)
+	}
+
+	for _, bad := range []jsonflex.Object{{}, {"a": jsonflex.Number(1), "b": jsonflex.Number(2)}} {
+		_, err := jsonflex.AsMapFromEntries(jsonflex.AsInt32())(jsonflex.Array{jsonflex.Object{"c": jsonflex.Number(3)}, bad})
+		if !errors.Is(err, jsonflex.ErrCannotConvert) || !strings.Contains(err.Error(), "item 1") {
+			t.Errorf("expected conversion error naming item 1, got %v", err)
+		}
+	}
+
+	dupes := append(entries, jsonflex.Object{"a": jsonflex.Number(3)})
+	if _, err := jsonflex.AsMapFromEntries(jsonflex.AsInt32())(dupes); !errors.Is(err, jsonflex.ErrDuplicateKey) {
+		t.Errorf("expected duplicate key error, got %v", err)
+	}
+	got, err = jsonflex.AsMapFromEntriesWith(jsonflex.AsInt32(), jsonflex.DuplicateKeepLast)(dupes)
+	if err != nil || got["a"] != 3 {
+		t.Errorf("expected last value 3 for key a, got %v with error %v", got, err)
+	}
+}
+
+func TestAsEnumerated(t *testing.T) {
+	obj := jsonflex.Object{"steps": jsonflex.Array{"mix", "bake"}}
+	got, err := jsonflex.GetField(obj, "steps", jsonflex.AsEnumerated(jsonflex.AsString()))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := []jsonflex.IndexedValue[string]{{Index: 0, Value: "mix"}, {Index: 1, Value: "bake"}}
+	if diff := cmp.Diff(expected, got); diff != "" {
+		t.Errorf("mismatch (-want +got):\n%s", diff)
+	}
+
+	_, err = jsonflex.AsEnumerated(jsonflex.AsString())(jsonflex.Array{"mix", nil})
+	if !errors.Is(err, jsonflex.ErrNullValue) || !strings.Contains(err.Error(), "item 1") {
+		t.Errorf("expected null value error naming item 1, got %v", err)
+	}
+}
+
+func TestAsArrayMax(t *testing.T) {
+	arr := jsonflex.Array{"a", "b", "c"}
+	got, err := jsonflex.AsArrayMax(3, jsonflex.AsString())(arr)
+	if err != nil || len(got) != 3 {
+		t.Errorf("expected 3 elements, got %v with error %v", got, err)
+	}
+
+	// The converter must not run on any element of an oversized array.
+	calls := 0
+	counting := func(v any) (string, error) {
+		calls++
+		return jsonflex.AsString()(v)
+	}
+	_, err = jsonflex.AsArrayMax(2, counting)(arr)
+	if !errors.Is(err, jsonflex.ErrTooManyElements) || calls != 0 {
+		t.Errorf("expected too many elements error without conversion, got %v after %d calls", err, calls)
+	}
+
+	if _, err := jsonflex.AsArrayMax(2, jsonflex.AsString())(nil); !errors.Is(err, jsonflex.ErrNullValue) {
+		t.Errorf("expected null value error, got %v", err)
+	}
+}
+
+func TestAsPartition(t *testing.T) {
+	arr := jsonflex.Array{jsonflex.Number(1), jsonflex.Number(2), jsonflex.Number(3), jsonflex.Number(4)}
+	even := func(v int32) bool { return v%2 == 0 }
+	got, err := jsonflex.AsPartition(jsonflex.AsInt32(), even)(arr)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := jsonflex.Partition[int32]{Matched: []int32{2, 4}, Rest: []int32{1, 3}}
+	if diff := cmp.Diff(expected, got); diff != "" {
+		t.Errorf("mismatch (-want +got):\n%s", diff)
+	}
+
+	_, err = jsonflex.AsPartition(jsonflex.AsInt32(), even)(jsonflex.Array{jsonflex.Number(1), "x"})
+	if !errors.Is(err, jsonflex.ErrCannotConvert) || !strings.Contains(err.Error(), "item 1") {
+		t.Errorf("expected conversion error naming item 1, got %v", err)
+	}
+}
+
+func TestAsArrayPtr(t *testing.T) {
+	got, err := jsonflex.AsArrayPtr(jsonflex.AsObject[Genre]())(jsonflex.Array{
+		jsonflex.Object{"id": jsonflex.Number(28)},
+		nil,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got) != 2 || got[0] == nil || assertNoError(got[0].ID())(t) != 28 || got[1] != nil {
+		t.Errorf("expected [&{id: 28}, nil], got %v", got)
+	}
+
+	_, err = jsonflex.AsArrayPtr(jsonflex.AsObject[Genre]())(jsonflex.Array{nil, "x"})
+	if !errors.Is(err, jsonflex.ErrCannotConvert) || !strings.Contains(err.Error(), "item 1") {
+		t.Errorf("expected conversion error naming item 1, got %v", err)
+	}
+}
+
+func TestAsArrayIndexed(t *testing.T) {
+	// Even positions hold names, odd positions hold numeric IDs.
+	convFor := func(i int) jsonflex.Converter[string] {
+		if i%2 == 0 {
+			return jsonflex.AsString()
+		}
+		return func(v any) (string, error) {
+			id, err := jsonflex.AsInt32()(v)
+			return fmt.Sprintf("#%d", id), err
+		}
+	}
+	got, err := jsonflex.AsArrayIndexed(convFor)(jsonflex.Array{"Action", jsonflex.Number(28), "Drama", jsonflex.Number(18)})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if diff := cmp.Diff([]string{"Action", "#28", "Drama", "#18"}, got); diff != "" {
+		t.Errorf("mismatch (-want +got):\n%s", diff)
+	}
+
+	_, err = jsonflex.AsArrayIndexed(convFor)(jsonflex.Array{"Action", "Drama"})
+	if !errors.Is(err, jsonflex.ErrCannotConvert) || !strings.Contains(err.Error(), "item 1") {
+		t.Errorf("expected conversion error naming item 1, got %v", err)
+	}
+
+	firstOnly := func(i int) jsonflex.Converter[string] {
+		if i == 0 {
+			return jsonflex.AsString()
+		}
+		return nil
+	}
+	_, err = jsonflex.AsArrayIndexed(firstOnly)(jsonflex.Array{"a", "b"})
+	if err == nil || !strings.Contains(err.Error(), "item 1") {
+		t.Errorf("expected error naming item 1 for nil converter, got %v", err)
+	}
+}
+
+func TestAsArrayCollect(t *testing.T) {
+	got, err := jsonflex.AsArrayCollect(jsonflex.AsInt32())(jsonflex.Array{jsonflex.Number(1), jsonflex.Number(2)})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if diff := cmp.Diff([]int32{1, 2}, got); diff != "" {
+		t.Errorf("mismatch (-want +got):\n%s", diff)
+	}
+
+	bad := jsonflex.Array{"a", jsonflex.Number(1), "b", nil, "c"}
+	_, err = jsonflex.AsArrayCollect(jsonflex.AsInt32())(bad)
+	if !errors.Is(err, jsonflex.ErrCannotConvert) || !errors.Is(err, jsonflex.ErrNullValue) {
+		t.Errorf("expected both conversion and null value errors, got %v", err)
+	}
+	for _, want := range []string{"item 0", "item 2", "item 3", "item 4"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("expected error to mention %q, got %v", want, err)
+		}
+	}
+
+	_, err = jsonflex.AsArrayCollectN(2, jsonflex.AsInt32())(bad)
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	msg := err.Error()
+	if !strings.Contains(msg, "item 2") || strings.Contains(msg, "item 3") || !strings.Contains(msg, "... and 2 more (4 errors in total)") {
+		t.Errorf("expected two stored errors and a summary, got %v", err)
+	}
+}
+
+func TestAsArrayCompact(t *testing.T) {
+	arr := jsonflex.Array{nil, "a", nil, nil, "b", nil}
+	got, err := jsonflex.AsArrayCompact(jsonflex.AsString())(arr)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if diff := cmp.Diff([]string{"a", "b"}, got); diff != "" {
+		t.Errorf("mismatch (-want +got):\n%s", diff)
+	}
+
+	_, err = jsonflex.AsArrayCompact(jsonflex.AsString())(jsonflex.Array{nil, "a", jsonflex.Number(1)})
+	if !errors.Is(err, jsonflex.ErrCannotConvert) || !strings.Contains(err.Error(), "item 2") {
+		t.Errorf("expected conversion error naming item 2, got %v", err)
+	}
+}
+
+func TestAsMatrix(t *testing.T) {
+	ragged := jsonflex.Array{
+		jsonflex.Array{jsonflex.Number(1), jsonflex.Number(2)},
+		jsonflex.Array{jsonflex.Number(3)},
+	}
+	got, err := jsonflex.AsMatrix()(ragged)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if diff := cmp.Diff([][]float64{{1, 2}, {3}}, got); diff != "" {
+		t.Errorf("mismatch (-want +got):\n%s", diff)
+	}
+
+	if _, err := jsonflex.AsRectMatrix()(ragged); !errors.Is(err, jsonflex.ErrCannotConvert) || !strings.Contains(err.Error(), "row 1") {
+		t.Errorf("expected ragged row error naming row 1, got %v", err)
+	}
+	rect := jsonflex.Array{
+		jsonflex.Array{jsonflex.Number(1), jsonflex.Number(2)},
+		jsonflex.Array{jsonflex.Number(3), jsonflex.Number(4)},
+	}
+	if _, err := jsonflex.AsRectMatrix()(rect); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	bad := jsonflex.Array{
+		jsonflex.Array{jsonflex.Number(1)},
+		jsonflex.Array{jsonflex.Number(2), "x"},
+	}
+	if _, err := jsonflex.AsMatrix()(bad); !errors.Is(err, jsonflex.ErrCannotConvert) || !strings.Contains(err.Error(), "item [1][1]") {
+		t.Errorf("expected error naming [1][1], got %v", err)
+	}
+	if _, err := jsonflex.AsArray2D(jsonflex.AsString())(jsonflex.Array{"x"}); !errors.Is(err, jsonflex.ErrCannotConvert) || !strings.Contains(err.Error(), "row 0") {
+		t.Errorf("expected error naming row 0, got %v", err)
+	}
+}
+
+func TestAsArrayHeadTail(t *testing.T) {
+	arr := jsonflex.Array{"a", "b", "c", jsonflex.Number(1)}
+	cases := []struct {
+		name     string
+		conv     jsonflex.Converter[[]string]
+		input    jsonflex.Array
+		expected []string
+	}{
+		{name: "head", conv: jsonflex.AsArrayHead(2, jsonflex.AsString()), input: arr, expected: []string{"a", "b"}},
+		{name: "head zero", conv: jsonflex.AsArrayHead(0, jsonflex.AsString()), input: arr, expected: []string{}},
+		{name: "head all", conv: jsonflex.AsArrayHead(10, jsonflex.AsString()), input: arr[:3], expected: []string{"a", "b", "c"}},
+		{name: "tail", conv: jsonflex.AsArrayTail(2, jsonflex.AsString()), input: arr[:3], expected: []string{"b", "c"}},
+		{name: "tail negative", conv: jsonflex.AsArrayTail(-1, jsonflex.AsString()), input: arr, expected: []string{}},
+		{name: "tail all", conv: jsonflex.AsArrayTail(10, jsonflex.AsString()), input: arr[:3], expected: []string{"a", "b", "c"}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got, err := c.conv(c.input)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(c.expected, got); diff != "" {
+				t.Errorf("mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+
+	_, err := jsonflex.AsArrayTail(1, jsonflex.AsString())(arr)
+	if !errors.Is(err, jsonflex.ErrCannotConvert) || !strings.Contains(err.Error(), "item 3") {
+		t.Errorf("expected error naming the original index 3, got %v", err)
+	}
+	if _, err := jsonflex.AsArrayHead(1, jsonflex.AsString())(nil); !errors.Is(err, jsonflex.ErrNullValue) {
+		t.Errorf("expected null value error, got %v", err)
+	}
+	if _, err := jsonflex.AsArrayTail(1, jsonflex.AsString())("x"); !errors.Is(err, jsonflex.ErrCannotConvert) {
+		t.Errorf("expected conversion error, got %v", err)
+	}
+}
+
+func BenchmarkAsArrayTail(b *testing.B) {
+	arr := make(jsonflex.Array, 1_000_000)
+	for i := range arr {
+		arr[i] = jsonflex.Number(i)
+	}
+	conv := jsonflex.AsArrayTail(3, jsonflex.AsInt32())
+	b.ReportAllocs()
+	for b.Loop() {
+		if _, err := conv(arr); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestAsArrayPairs(t *testing.T) {
+	got, err := jsonflex.AsArrayPairs(jsonflex.AsFloat64())(jsonflex.Array{
+		jsonflex.Number(1), jsonflex.Number(2), jsonflex.Number(3), jsonflex.Number(4),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if diff := cmp.Diff([][2]float64{{1, 2}, {3, 4}}, got); diff != "" {
+		t.Errorf("mismatch (-want +got):\n%s", diff)
+	}
+
+	_, err = jsonflex.AsArrayPairs(jsonflex.AsFloat64())(jsonflex.Array{jsonflex.Number(1), jsonflex.Number(2), jsonflex.Number(3)})
+	if !errors.Is(err, jsonflex.ErrCannotConvert) || !strings.Contains(err.Error(), "odd length 3") {
+		t.Errorf("expected odd length error, got %v", err)
+	}
+	_, err = jsonflex.AsArrayPairs(jsonflex.AsFloat64())(jsonflex.Array{jsonflex.Number(1), "y"})
+	if !errors.Is(err, jsonflex.ErrCannotConvert) || !strings.Contains(err.Error(), "item 1") {
+		t.Errorf("expected error naming item 1, got %v", err)
+	}
+}
+
+func TestAsEnumArray(t *testing.T) {
+	conv := jsonflex.AsEnumArray[Status]("active", "inactive")
+	got, err := conv(jsonflex.Array{"active", "inactive", "active"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if diff := cmp.Diff([]Status{"active", "inactive", "active"}, got); diff != "" {
+		t.Errorf("mismatch (-want +got):\n%s", diff)
+	}
+
+	_, err = conv(jsonflex.Array{"active", "deleted", "inactive", "archived"})
+	if !errors.Is(err, jsonflex.ErrCannotConvert) {
+		t.Errorf("expected conversion error, got %v", err)
+	}
+	for _, want := range []string{`item 1: `, `"deleted"`, `item 3: `, `"archived"`} {
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("expected error to mention %s, got %v", want, err)
+		}
+	}
+}
+
+func TestAsArrayLookup(t *testing.T) {
+	name := func(g Genre) string {
+		n, _ := g.Name()
+		return n
+	}
+	input := jsonflex.Array{
+		jsonflex.Object{"id": jsonflex.Number(28), "name": "Action"},
+		jsonflex.Object{"id": jsonflex.Number(12), "name": "Adventure"},
+		jsonflex.Object{"id": jsonflex.Number(29), "name": "ACTION"},
+	}
+	id := func(g Genre) int32 {
+		i, _ := g.ID()
+		return i
+	}
+
+	got, err := jsonflex.AsArrayLookup(jsonflex.AsObject[Genre](), name)(input[:2])
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if g, ok := got["action"]; !ok || id(g) != 28 {
+		t.Errorf("expected lowercased key action to map to id 28, got %v", got)
+	}
+	if _, ok := got["Adventure"]; ok {
+		t.Error("expected keys to be lowercased")
+	}
+
+	_, err = jsonflex.AsArrayLookup(jsonflex.AsObject[Genre](), name)(input)
+	if !errors.Is(err, jsonflex.ErrDuplicateKey) || !strings.Contains(err.Error(), "item 2") {
+		t.Errorf("expected duplicate key error naming item 2, got %v", err)
+	}
+
+	for _, c := range []struct {
+		dup      jsonflex.DuplicatePolicy
+		expected int32
+	}{
+		{dup: jsonflex.DuplicateKeepFirst, expected: 28},
+		{dup: jsonflex.DuplicateKeepLast, expected: 29},
+	} {
+		got, err := jsonflex.AsArrayLookupWith(jsonflex.AsObject[Genre](), name, c.dup)(input)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if id(got["action"]) != c.expected {
+			t.Errorf("policy %v: expected id %d, got %d", c.dup, c.expected, id(got["action"]))
+		}
+	}
+}
+
+func TestAsRepeated(t *testing.T) {
+	cases := []struct {
+		name     string
+		input    any
+		expected []string
+	}{
+		{name: "null", input: nil, expected: []string{}},
+		{name: "single", input: "a", expected: []string{"a"}},
+		{name: "array", input: jsonflex.Array{"a", "b"}, expected: []string{"a", "b"}},
+		{name: "empty array", input: jsonflex.Array{}, expected: []string{}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got, err := jsonflex.AsRepeated(jsonflex.AsString())(c.input)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(c.expected, got); diff != "" {
+				t.Errorf("mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+
+	if _, err := jsonflex.AsRepeated(jsonflex.AsString())(jsonflex.Number(1)); !errors.Is(err, jsonflex.ErrCannotConvert) {
+		t.Errorf("expected conversion error, got %v", err)
+	}
+	if _, err := jsonflex.AsRepeated(jsonflex.AsString())(jsonflex.Array{"a", true}); !errors.Is(err, jsonflex.ErrCannotConvert) {
+		t.Errorf("expected conversion error, got %v", err)
+	}
+
+	absent := func() (any, bool) { return nil, true }
+	got, err := jsonflex.GetFieldFallback(jsonflex.Object{}, "tags", jsonflex.AsRepeated(jsonflex.AsString()), absent)
+	if err != nil || len(got) != 0 {
+		t.Errorf("expected empty slice for absent field, got %v with error %v", got, err)
+	}
+}
+
+func TestAsDeinterleave(t *testing.T) {
+	cases := []struct {
+		name     string
+		input    jsonflex.Array
+		expected jsonflex.Deinterleaved[string]
+	}{
+		{
+			name:     "even length",
+			input:    jsonflex.Array{"a0", "b0", "a1", "b1"},
+			expected: jsonflex.Deinterleaved[string]{Even: []string{"a0", "a1"}, Odd: []string{"b0", "b1"}},
+		},
+		{
+			name:     "odd length",
+			input:    jsonflex.Array{"a0", "b0", "a1"},
+			expected: jsonflex.Deinterleaved[string]{Even: []string{"a0", "a1"}, Odd: []string{"b0"}},
+		},
+		{
+			name:     "empty",
+			input:    jsonflex.Array{},
+			expected: jsonflex.Deinterleaved[string]{Even: []string{}, Odd: []string{}},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got, err := jsonflex.AsDeinterleave(jsonflex.AsString())(c.input)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(c.expected, got); diff != "" {
+				t.Errorf("mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+
+	if _, err := jsonflex.AsDeinterleave(jsonflex.AsString())(jsonflex.Array{"a", true}); !errors.Is(err, jsonflex.ErrCannotConvert) {
+		t.Errorf("expected conversion error, got %v", err)
+	}
+}
+
+func TestAsArrayStride(t *testing.T) {
+	arr := jsonflex.Array{"a", jsonflex.Number(1), "b", jsonflex.Number(2), "c"}
+	got, err := jsonflex.AsArrayStride(2, jsonflex.AsString())(arr)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if diff := cmp.Diff([]string{"a", "b", "c"}, got); diff != "" {
+		t.Errorf("mismatch (-want +got):\n%s", diff)
+	}
+
+	got, err = jsonflex.AsArrayStride(10, jsonflex.AsString())(arr)
+	if err != nil || len(got) != 1 || got[0] != "a" {
+		t.Errorf("expected [a], got %v with error %v", got, err)
+	}
+	_, err = jsonflex.AsArrayStride(3, jsonflex.AsString())(arr)
+	if !errors.Is(err, jsonflex.ErrCannotConvert) || !strings.Contains(err.Error(), "item 3") {
+		t.Errorf("expected error naming item 3, got %v", err)
+	}
+	if _, err := jsonflex.AsArrayStride(0, jsonflex.AsString())(arr); err == nil {
+		t.Error("expected error for step 0")
+	}
+}
+
+func TestAsArraySample(t *testing.T) {
+	arr := make(jsonflex.Array, 100)
+	for i := range arr {
+		arr[i] = jsonflex.Number(i)
+	}
+	sample := jsonflex.AsArraySample(10, 42, jsonflex.AsInt32())
+	got, err := sample(arr)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got) != 10 {
+		t.Fatalf("expected 10 elements, got %d", len(got))
+	}
+	for i := 1; i < len(got); i++ {
+		if got[i] <= got[i-1] {
+			t.Errorf("expected elements in original order, got %v", got)
+			break
+		}
+	}
+	again, err := sample(arr)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if diff := cmp.Diff(got, again); diff != "" {
+		t.Errorf("expected the same sample for the same seed (-first +second):\n%s", diff)
+	}
+	other, err := jsonflex.AsArraySample(10, 7, jsonflex.AsInt32())(arr)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cmp.Equal(got, other) {
+		t.Errorf("expected a different sample for a different seed, got %v twice", got)
+	}
+
+	all, err := jsonflex.AsArraySample(10, 1, jsonflex.AsString())(jsonflex.Array{"a", "b"})
+	if diff := cmp.Diff([]string{"a", "b"}, all); err != nil || diff != "" {
+		t.Errorf("expected every element of a short array, got %v with error %v", all, err)
+	}
+	none, err := jsonflex.AsArraySample(0, 1, jsonflex.AsString())(jsonflex.Array{"a", "b"})
+	if err != nil || len(none) != 0 {
+		t.Errorf("expected no elements, got %v with error %v", none, err)
+	}
+	_, err = jsonflex.AsArraySample(3, 1, jsonflex.AsString())(jsonflex.Array{"a", true, "c"})
+	if !errors.Is(err, jsonflex.ErrCannotConvert) || !strings.Contains(err.Error(), "item 1") {
+		t.Errorf("expected error naming item 1, got %v", err)
+	}
+}
+
+func TestAsRLE(t *testing.T) {
+	got, err := jsonflex.AsRLE(jsonflex.AsString())(jsonflex.Array{"a", "a", "a", "b", "c", "c", "a"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := []jsonflex.Run[string]{
+		{Value: "a", Count: 3},
+		{Value: "b", Count: 1},
+		{Value: "c", Count: 2},
+		{Value: "a", Count: 1},
+	}
+	if diff := cmp.Diff(expected, got); diff != "" {
+		t.Errorf("mismatch (-want +got):\n%s", diff)
+	}
+
+	got, err = jsonflex.AsRLE(jsonflex.AsString())(jsonflex.Array{})
+	if err != nil || got == nil || len(got) != 0 {
+		t.Errorf("expected no runs, got %#v with error %v", got, err)
+	}
+	_, err = jsonflex.AsRLE(jsonflex.AsString())(jsonflex.Array{"a", jsonflex.Number(1)})
+	if !errors.Is(err, jsonflex.ErrCannotConvert) || !strings.Contains(err.Error(), "item 1") {
+		t.Errorf("expected conversion error naming item 1, got %v", err)
+	}
+}
+
+func TestAsSortedSet(t *testing.T) {
+	got, err := jsonflex.AsSortedSet(jsonflex.AsInt32())(jsonflex.Array{
+		jsonflex.Number(28), jsonflex.Number(12), jsonflex.Number(28), jsonflex.Number(-1), jsonflex.Number(12), jsonflex.Number(878),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if diff := cmp.Diff([]int32{-1, 12, 28, 878}, got); diff != "" {
+		t.Errorf("mismatch (-want +got):\n%s", diff)
+	}
+
+	names, err := jsonflex.AsSortedSet(jsonflex.AsString())(jsonflex.Array{"b", "a", "b"})
+	if diff := cmp.Diff([]string{"a", "b"}, names); err != nil || diff != "" {
+		t.Errorf("expected [a b], got %v with error %v", names, err)
+	}
+	_, err = jsonflex.AsSortedSet(jsonflex.AsString())(jsonflex.Array{"a", jsonflex.Number(1)})
+	if !errors.Is(err, jsonflex.ErrCannotConvert) || !strings.Contains(err.Error(), "item 1") {
+		t.Errorf("expected conversion error naming item 1, got %v", err)
+	}
+}
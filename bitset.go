@@ -0,0 +1,64 @@
+package jsonflex
+
+import (
+	"fmt"
+	"math"
+	"math/bits"
+)
+
+// Bitset is a fixed-size set of small non-negative integers, packed one bit
+// per possible member. It is produced by AsBitset.
+type Bitset struct {
+	words []uint64
+	size  int
+}
+
+// Test reports whether i is in the set. Values outside the range the set was
+// built for are never members.
+func (b *Bitset) Test(i int) bool {
+	if i < 0 || i >= b.size {
+		return false
+	}
+	return b.words[i/64]&(1<<(i%64)) != 0
+}
+
+// Count returns the number of members of the set.
+func (b *Bitset) Count() int {
+	n := 0
+	for _, w := range b.words {
+		n += bits.OnesCount64(w)
+	}
+	return n
+}
+
+// AsBitset returns a Converter that converts an array of integers in
+// [0, size) into a Bitset, which makes membership checks on ID lists cheap.
+// Duplicates are allowed. Non-integral, negative, or out-of-range elements
+// are rejected with ErrCannotConvert, naming their index. A negative size is
+// an error.
+func AsBitset(size int) Converter[*Bitset] {
+	return func(v any) (*Bitset, error) {
+		if size < 0 {
+			return nil, fmt.Errorf("AsBitset: size must not be negative, got %d", size)
+		}
+		arr, err := rawArray(v)
+		if err != nil {
+			return nil, err
+		}
+		b := &Bitset{words: make([]uint64, (size+63)/64), size: size}
+		for i, item := range arr {
+			f, err := Float64(item)
+			if err != nil {
+				return nil, itemError(i, item, err)
+			}
+			if f < 0 || f >= float64(size) || f != math.Trunc(f) {
+				ce := newConversionError(item, "Bitset member")
+				ce.Reason = fmt.Sprintf("%v is not an integer in [0, %d)", f, size)
+				return nil, itemError(i, item, ce)
+			}
+			n := int(f)
+			b.words[n/64] |= 1 << (n % 64)
+		}
+		return b, nil
+	}
+}
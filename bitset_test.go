@@ -0,0 +1,53 @@
+package jsonflex_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/krelinga/go-jsonflex"
+)
+
+func TestAsBitset(t *testing.T) {
+	ids := jsonflex.Array{jsonflex.Number(0), jsonflex.Number(28), jsonflex.Number(64), jsonflex.Number(99), jsonflex.Number(28)}
+	b, err := jsonflex.AsBitset(100)(ids)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, i := range []int{0, 28, 64, 99} {
+		if !b.Test(i) {
+			t.Errorf("expected %d to be a member", i)
+		}
+	}
+	for _, i := range []int{-1, 1, 63, 65, 100, 1000} {
+		if b.Test(i) {
+			t.Errorf("expected %d not to be a member", i)
+		}
+	}
+	if b.Count() != 4 {
+		t.Errorf("expected 4 members, got %d", b.Count())
+	}
+
+	for _, c := range []struct {
+		input jsonflex.Array
+		want  string
+	}{
+		{input: jsonflex.Array{jsonflex.Number(100)}, want: "100 is not an integer in [0, 100)"},
+		{input: jsonflex.Array{jsonflex.Number(1), jsonflex.Number(-1)}, want: "item 1"},
+		{input: jsonflex.Array{jsonflex.Number(1.5)}, want: "1.5 is not an integer"},
+		{input: jsonflex.Array{jsonflex.Number(1e300)}, want: "is not an integer in"},
+		{input: jsonflex.Array{"1"}, want: "item 0"},
+	} {
+		_, err := jsonflex.AsBitset(100)(c.input)
+		if !errors.Is(err, jsonflex.ErrCannotConvert) || !strings.Contains(err.Error(), c.want) {
+			t.Errorf("%v: expected conversion error mentioning %q, got %v", c.input, c.want, err)
+		}
+	}
+
+	if _, err := jsonflex.AsBitset(-128)(jsonflex.Array{}); err == nil {
+		t.Error("expected error for negative size")
+	}
+	if b, err := jsonflex.AsBitset(0)(jsonflex.Array{}); err != nil || b.Count() != 0 {
+		t.Errorf("expected empty set for size 0, got %v with error %v", b, err)
+	}
+}
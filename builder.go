@@ -0,0 +1,76 @@
+package jsonflex
+
+import "maps"
+
+// ObjectBuilder constructs an Object fluently, storing values in the forms the
+// converters expect (Number for numbers, Array for arrays).
+// The zero value is ready to use. Each setter returns the builder so calls
+// can be chained.
+type ObjectBuilder struct {
+	obj Object
+}
+
+// NewObjectBuilder returns an empty ObjectBuilder.
+func NewObjectBuilder() *ObjectBuilder {
+	return &ObjectBuilder{}
+}
+
+// Set stores value under key. Go numeric values are stored as Number; all
+// other values are stored as given.
+func (b *ObjectBuilder) Set(key string, value any) *ObjectBuilder {
+	if b.obj == nil {
+		b.obj = make(Object)
+	}
+	if f, ok := normalizeNumber(value); ok {
+		value = f
+	}
+	b.obj[key] = value
+	return b
+}
+
+// SetInt stores i under key as a Number.
+func (b *ObjectBuilder) SetInt(key string, i int) *ObjectBuilder {
+	return b.Set(key, Number(i))
+}
+
+// SetNumber stores f under key as a Number.
+func (b *ObjectBuilder) SetNumber(key string, f float64) *ObjectBuilder {
+	return b.Set(key, Number(f))
+}
+
+// SetString stores s under key.
+func (b *ObjectBuilder) SetString(key string, s string) *ObjectBuilder {
+	return b.Set(key, s)
+}
+
+// SetBool stores v under key.
+func (b *ObjectBuilder) SetBool(key string, v bool) *ObjectBuilder {
+	return b.Set(key, v)
+}
+
+// SetNull stores a JSON null under key.
+func (b *ObjectBuilder) SetNull(key string) *ObjectBuilder {
+	return b.Set(key, nil)
+}
+
+// SetArray stores values under key as an Array. Go numeric elements are
+// stored as Number.
+func (b *ObjectBuilder) SetArray(key string, values ...any) *ObjectBuilder {
+	arr := make(Array, len(values))
+	for i, value := range values {
+		if f, ok := normalizeNumber(value); ok {
+			value = f
+		}
+		arr[i] = value
+	}
+	return b.Set(key, arr)
+}
+
+// Build returns the constructed Object. The builder keeps its own copy, so
+// it can continue to be used without affecting the returned Object.
+func (b *ObjectBuilder) Build() Object {
+	if b.obj == nil {
+		return Object{}
+	}
+	return maps.Clone(b.obj)
+}
@@ -0,0 +1,44 @@
+package jsonflex_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/krelinga/go-jsonflex"
+)
+
+func TestObjectBuilder(t *testing.T) {
+	b := jsonflex.NewObjectBuilder().
+		SetString("title", "Inception").
+		SetInt("id", 12345).
+		SetBool("adult", false).
+		SetArray("genre_ids", 28, 12, int64(878)).
+		SetNull("tagline").
+		Set("runtime", uint16(148))
+	obj := b.Build()
+
+	expected := jsonflex.Object{
+		"title":     "Inception",
+		"id":        jsonflex.Number(12345),
+		"adult":     false,
+		"genre_ids": jsonflex.Array{jsonflex.Number(28), jsonflex.Number(12), jsonflex.Number(878)},
+		"tagline":   nil,
+		"runtime":   jsonflex.Number(148),
+	}
+	if diff := cmp.Diff(expected, obj); diff != "" {
+		t.Errorf("mismatch (-want +got):\n%s", diff)
+	}
+
+	movie := Movie(obj)
+	if id := assertNoError(movie.ID())(t); id != 12345 {
+		t.Errorf("expected id 12345, got %d", id)
+	}
+
+	b.SetString("title", "Interstellar")
+	if obj["title"] != "Inception" {
+		t.Error("expected built Object to be unaffected by later builder calls")
+	}
+	if got := (&jsonflex.ObjectBuilder{}).Build(); got == nil || len(got) != 0 {
+		t.Errorf("expected empty non-nil Object from zero builder, got %v", got)
+	}
+}
@@ -0,0 +1,74 @@
+package jsonflex
+
+import (
+	"sync"
+)
+
+// CachedObject wraps an Object and memoizes the results of field conversions
+// made through CachedField. It assumes the underlying Object is not modified
+// after Cached is called; call Invalidate if it is. A CachedObject is safe for
+// concurrent use.
+type CachedObject struct {
+	obj   Object
+	mu    sync.Mutex
+	cache map[any]cacheEntry
+}
+
+type cacheEntry struct {
+	value any
+	err   error
+}
+
+// Cached returns a CachedObject wrapping obj.
+// This is an opt-in alternative to calling GetField directly in hot accessors.
+func Cached(obj Object) *CachedObject {
+	return &CachedObject{obj: obj}
+}
+
+// Object returns the wrapped Object.
+func (c *CachedObject) Object() Object {
+	return c.obj
+}
+
+// Invalidate discards all cached results.
+func (c *CachedObject) Invalidate() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.cache = nil
+}
+
+// CachedField is a field key bound to the Converter that reads it.
+// Results are cached per CachedField rather than per key, so two fields that
+// read the same key with different converters, such as AsString and a
+// validating AsMatching, never share results. Create each CachedField once,
+// typically as a package-level variable next to its accessor, and reuse it.
+type CachedField[T any] struct {
+	key  string
+	conv Converter[T]
+}
+
+// NewCachedField returns a CachedField that reads key with conv.
+func NewCachedField[T any](key string, conv Converter[T]) *CachedField[T] {
+	return &CachedField[T]{key: key, conv: conv}
+}
+
+// Get is like GetField on the Object wrapped by c, but caches the result
+// (including any error) in c. Every call returns the same cached value, so
+// slices, maps and Objects in results must not be modified by callers.
+func (f *CachedField[T]) Get(c *CachedObject) (T, error) {
+	c.mu.Lock()
+	entry, ok := c.cache[f]
+	c.mu.Unlock()
+	if ok {
+		value, _ := entry.value.(T)
+		return value, entry.err
+	}
+	value, err := GetField(c.obj, f.key, f.conv)
+	c.mu.Lock()
+	if c.cache == nil {
+		c.cache = make(map[any]cacheEntry)
+	}
+	c.cache[f] = cacheEntry{value: value, err: err}
+	c.mu.Unlock()
+	return value, err
+}
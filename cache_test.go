@@ -0,0 +1,95 @@
+package jsonflex_test
+
+import (
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/krelinga/go-jsonflex"
+)
+
+var (
+	cachedTitle   = jsonflex.NewCachedField("title", jsonflex.AsString())
+	cachedMissing = jsonflex.NewCachedField("missing", jsonflex.AsString())
+)
+
+func TestCached(t *testing.T) {
+	obj := jsonflex.Object{"title": "Inception", "id": jsonflex.Number(1)}
+	c := jsonflex.Cached(obj)
+
+	title, err := cachedTitle.Get(c)
+	if err != nil || title != "Inception" {
+		t.Errorf("expected title 'Inception', got %q with error %v", title, err)
+	}
+	if _, err := cachedMissing.Get(c); !errors.Is(err, jsonflex.ErrFieldNotFound) {
+		t.Errorf("expected field not found error, got %v", err)
+	}
+
+	// Cached results survive mutation until Invalidate is called.
+	obj["title"] = "Interstellar"
+	title, _ = cachedTitle.Get(c)
+	if title != "Inception" {
+		t.Errorf("expected cached title 'Inception', got %q", title)
+	}
+	c.Invalidate()
+	title, _ = cachedTitle.Get(c)
+	if title != "Interstellar" {
+		t.Errorf("expected refreshed title 'Interstellar', got %q", title)
+	}
+}
+
+func TestCachedFieldsAreIndependent(t *testing.T) {
+	c := jsonflex.Cached(jsonflex.Object{"sku": "bad"})
+	plain := jsonflex.NewCachedField("sku", jsonflex.AsString())
+	validated := jsonflex.NewCachedField("sku", jsonflex.AsMatching(regexp.MustCompile(`^[A-Z]{3}-\d+$`)))
+	if sku, err := plain.Get(c); err != nil || sku != "bad" {
+		t.Errorf("expected 'bad', got %q with error %v", sku, err)
+	}
+	if _, err := validated.Get(c); !errors.Is(err, jsonflex.ErrCannotConvert) {
+		t.Errorf("expected the validating converter to run, got %v", err)
+	}
+}
+
+func TestCachedNilInterface(t *testing.T) {
+	c := jsonflex.Cached(jsonflex.Object{"a": nil})
+	null := jsonflex.NewCachedField("a", jsonflex.AsAny())
+	missing := jsonflex.NewCachedField("missing", jsonflex.AsAny())
+	for i := range 2 {
+		if v, err := null.Get(c); err != nil || v != nil {
+			t.Errorf("call %d: expected nil for null field, got %v with error %v", i, v, err)
+		}
+		if v, err := missing.Get(c); !errors.Is(err, jsonflex.ErrFieldNotFound) || v != nil {
+			t.Errorf("call %d: expected field not found error, got %v with error %v", i, v, err)
+		}
+	}
+}
+
+func benchmarkObject() jsonflex.Object {
+	ids := make(jsonflex.Array, 100)
+	for i := range ids {
+		ids[i] = jsonflex.Number(i)
+	}
+	return jsonflex.Object{"genre_ids": ids}
+}
+
+func BenchmarkGetFieldRepeated(b *testing.B) {
+	obj := benchmarkObject()
+	conv := jsonflex.AsArray(jsonflex.AsInt32())
+	b.ReportAllocs()
+	for b.Loop() {
+		if _, err := jsonflex.GetField(obj, "genre_ids", conv); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkCachedFieldRepeated(b *testing.B) {
+	c := jsonflex.Cached(benchmarkObject())
+	field := jsonflex.NewCachedField("genre_ids", jsonflex.AsArray(jsonflex.AsInt32()))
+	b.ReportAllocs()
+	for b.Loop() {
+		if _, err := field.Get(c); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
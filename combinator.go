@@ -0,0 +1,96 @@
+package jsonflex
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// OneOf returns a Converter that tries each of convs in order and returns the
+// result of the first one that succeeds.
+// If every converter fails, the returned error joins all of their errors, so
+// errors.Is matches any sentinel they reported.
+func OneOf[T any](convs ...Converter[T]) Converter[T] {
+	return func(v any) (T, error) {
+		errs := make([]error, 0, len(convs))
+		for _, conv := range convs {
+			result, err := conv(v)
+			if err == nil {
+				return result, nil
+			}
+			errs = append(errs, err)
+		}
+		var zero T
+		if len(errs) == 0 {
+			return zero, fmt.Errorf("%w %T: no converters given", ErrCannotConvert, v)
+		}
+		return zero, errors.Join(errs...)
+	}
+}
+
+// FirstSuccessOr is like OneOf, but falls back to fallback if every one of
+// convs fails. The fallback is a last resort for values whose shape is not
+// (yet) known, such as AsAny to preserve them verbatim, and should be chosen
+// so that it does not fail. If it does fail anyway, its error is joined with
+// those of convs.
+func FirstSuccessOr[T any](fallback Converter[T], convs ...Converter[T]) Converter[T] {
+	return func(v any) (T, error) {
+		result, err := OneOf(convs...)(v)
+		if err == nil {
+			return result, nil
+		}
+		result, fallbackErr := fallback(v)
+		if fallbackErr != nil {
+			var zero T
+			return zero, errors.Join(err, fmt.Errorf("fallback: %w", fallbackErr))
+		}
+		return result, nil
+	}
+}
+
+// WithContext returns a Converter that behaves like conv, but prefixes any
+// error with label, such as "is-adult flag: cannot convert string to bool".
+// The original error is wrapped, so errors.Is and errors.As still match it.
+func WithContext[T any](label string, conv Converter[T]) Converter[T] {
+	return func(v any) (T, error) {
+		result, err := conv(v)
+		if err != nil {
+			var zero T
+			return zero, fmt.Errorf("%s: %w", label, err)
+		}
+		return result, nil
+	}
+}
+
+// WithTimeout returns a Converter that runs conv in a separate goroutine and
+// fails with ErrTimeout if it has not finished within d, which protects
+// latency-sensitive callers from pathological input.
+//
+// Go cannot stop a running goroutine, so after a timeout conv keeps running
+// in the background until it returns on its own, and its result is discarded.
+// When the work itself must stop, prefer converters that observe a
+// context.Context, such as StreamArray.
+func WithTimeout[T any](d time.Duration, conv Converter[T]) Converter[T] {
+	type outcome struct {
+		value T
+		err   error
+	}
+	return func(v any) (T, error) {
+		// Buffered so that an abandoned conversion can still deliver its
+		// result and exit.
+		done := make(chan outcome, 1)
+		go func() {
+			value, err := conv(v)
+			done <- outcome{value, err}
+		}()
+		timer := time.NewTimer(d)
+		defer timer.Stop()
+		select {
+		case o := <-done:
+			return o.value, o.err
+		case <-timer.C:
+			var zero T
+			return zero, fmt.Errorf("%w after %v", ErrTimeout, d)
+		}
+	}
+}
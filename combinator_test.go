@@ -0,0 +1,105 @@
+package jsonflex_test
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/krelinga/go-jsonflex"
+)
+
+func TestOneOf(t *testing.T) {
+	// Accept either a numeric ID or a string ID rendered by its first byte.
+	id := jsonflex.OneOf(
+		jsonflex.AsInt32(),
+		func(v any) (int32, error) {
+			s, err := jsonflex.AsString()(v)
+			if err != nil || len(s) == 0 {
+				return 0, err
+			}
+			return int32(s[0]), nil
+		},
+	)
+	got, err := jsonflex.AsArray(id)(jsonflex.Array{jsonflex.Number(7), "A"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if diff := cmp.Diff([]int32{7, 65}, got); diff != "" {
+		t.Errorf("mismatch (-want +got):\n%s", diff)
+	}
+	if _, err := id(true); !errors.Is(err, jsonflex.ErrCannotConvert) {
+		t.Errorf("expected conversion error, got %v", err)
+	}
+	if _, err := id(nil); !errors.Is(err, jsonflex.ErrNullValue) {
+		t.Errorf("expected null value error, got %v", err)
+	}
+}
+
+func TestFirstSuccessOr(t *testing.T) {
+	known := jsonflex.FirstSuccessOr(
+		jsonflex.AsAny(),
+		func(v any) (any, error) { return jsonflex.AsObject[Genre]()(v) },
+	)
+	got, err := jsonflex.AsArray(known)(jsonflex.Array{
+		jsonflex.Object{"id": jsonflex.Number(1)},
+		"something new",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := got[0].(Genre); !ok {
+		t.Errorf("expected first element to be a Genre, got %T", got[0])
+	}
+	if got[1] != "something new" {
+		t.Errorf("expected second element to be preserved verbatim, got %v", got[1])
+	}
+
+	failing := jsonflex.FirstSuccessOr(jsonflex.AsString(), jsonflex.AsMatching(regexp.MustCompile(`^x$`)))
+	if _, err := failing(jsonflex.Number(1)); !errors.Is(err, jsonflex.ErrCannotConvert) {
+		t.Errorf("expected conversion error when fallback fails, got %v", err)
+	}
+}
+
+func TestWithContext(t *testing.T) {
+	conv := jsonflex.WithContext("is-adult flag", jsonflex.AsBool())
+	got, err := jsonflex.GetField(jsonflex.Object{"adult": true}, "adult", conv)
+	if err != nil || !got {
+		t.Errorf("expected true, got %v with error %v", got, err)
+	}
+
+	_, err = jsonflex.GetField(jsonflex.Object{"adult": "yes"}, "adult", conv)
+	if !errors.Is(err, jsonflex.ErrCannotConvert) {
+		t.Errorf("expected conversion error, got %v", err)
+	}
+	if err == nil || !strings.Contains(err.Error(), "is-adult flag: ") {
+		t.Errorf("expected error to be labeled, got %v", err)
+	}
+	var ce *jsonflex.ConversionError
+	if !errors.As(err, &ce) {
+		t.Errorf("expected ConversionError to be reachable, got %v", err)
+	}
+}
+
+func TestWithTimeout(t *testing.T) {
+	got, err := jsonflex.WithTimeout(time.Second, jsonflex.AsString())("fast")
+	if err != nil || got != "fast" {
+		t.Errorf("expected fast, got %q with error %v", got, err)
+	}
+	if _, err := jsonflex.WithTimeout(time.Second, jsonflex.AsString())(true); !errors.Is(err, jsonflex.ErrCannotConvert) {
+		t.Errorf("expected conversion error to pass through, got %v", err)
+	}
+
+	release := make(chan struct{})
+	defer close(release)
+	slow := func(v any) (string, error) {
+		<-release
+		return "slow", nil
+	}
+	_, err = jsonflex.WithTimeout(10*time.Millisecond, slow)("x")
+	if !errors.Is(err, jsonflex.ErrTimeout) {
+		t.Errorf("expected timeout error, got %v", err)
+	}
+}
@@ -0,0 +1,180 @@
+package jsonflex
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// ChangeKind classifies a Change reported by Diff.
+type ChangeKind int
+
+const (
+	// ChangeAdded means the value exists only in the new tree.
+	ChangeAdded ChangeKind = iota
+	// ChangeRemoved means the value exists only in the old tree.
+	ChangeRemoved
+	// ChangeModified means the value exists in both trees but differs.
+	ChangeModified
+)
+
+func (k ChangeKind) String() string {
+	switch k {
+	case ChangeAdded:
+		return "added"
+	case ChangeRemoved:
+		return "removed"
+	case ChangeModified:
+		return "changed"
+	default:
+		return fmt.Sprintf("ChangeKind(%d)", int(k))
+	}
+}
+
+// Change is a single structural difference between two JSON values.
+type Change struct {
+	// Path holds the object keys and array indices leading to the change.
+	Path []string
+	Kind ChangeKind
+	// Old is the value in the old tree; nil for ChangeAdded.
+	Old any
+	// New is the value in the new tree; nil for ChangeRemoved.
+	New any
+}
+
+// Diff returns the structural differences between a and b, recursively
+// comparing objects (including types based on Object), arrays, and primitive
+// values. Object keys are visited in sorted order, so the result is
+// deterministic. Numbers are compared by value regardless of their Go type,
+// so an int 5 equals a float64 5. A nil result means the values are equal.
+func Diff(a, b any) []Change {
+	var changes []Change
+	diff(a, b, nil, &changes)
+	return changes
+}
+
+// Equal reports whether a and b are structurally equal, that is, whether Diff
+// finds no changes between them.
+func Equal(a, b any) bool {
+	return len(Diff(a, b)) == 0
+}
+
+func diff(a, b any, path []string, changes *[]Change) {
+	at := func() []string { return slices.Clone(path) }
+	if aObj, ok := asPlainObject(a); ok {
+		if bObj, ok := asPlainObject(b); ok {
+			keys := make([]string, 0, len(aObj)+len(bObj))
+			for key := range aObj {
+				keys = append(keys, key)
+			}
+			for key := range bObj {
+				if _, exists := aObj[key]; !exists {
+					keys = append(keys, key)
+				}
+			}
+			slices.Sort(keys)
+			for _, key := range keys {
+				aValue, inA := aObj[key]
+				bValue, inB := bObj[key]
+				switch {
+				case !inA:
+					*changes = append(*changes, Change{Path: append(at(), key), Kind: ChangeAdded, New: bValue})
+				case !inB:
+					*changes = append(*changes, Change{Path: append(at(), key), Kind: ChangeRemoved, Old: aValue})
+				default:
+					diff(aValue, bValue, append(path, key), changes)
+				}
+			}
+			return
+		}
+	}
+	if aArr, ok := asPlainArray(a); ok {
+		if bArr, ok := asPlainArray(b); ok {
+			for i := 0; i < max(len(aArr), len(bArr)); i++ {
+				index := strconv.Itoa(i)
+				switch {
+				case i >= len(aArr):
+					*changes = append(*changes, Change{Path: append(at(), index), Kind: ChangeAdded, New: bArr[i]})
+				case i >= len(bArr):
+					*changes = append(*changes, Change{Path: append(at(), index), Kind: ChangeRemoved, Old: aArr[i]})
+				default:
+					diff(aArr[i], bArr[i], append(path, index), changes)
+				}
+			}
+			return
+		}
+	}
+	if aNum, ok := normalizeNumber(a); ok {
+		if bNum, ok := normalizeNumber(b); ok {
+			if aNum != bNum {
+				*changes = append(*changes, Change{Path: at(), Kind: ChangeModified, Old: a, New: b})
+			}
+			return
+		}
+	}
+	if !reflect.DeepEqual(a, b) {
+		*changes = append(*changes, Change{Path: at(), Kind: ChangeModified, Old: a, New: b})
+	}
+}
+
+// asPlainObject returns v as an Object if it is an Object or a type based on
+// Object, such as one with accessor methods.
+func asPlainObject(v any) (Object, bool) {
+	if obj, ok := v.(Object); ok {
+		return obj, true
+	}
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Map && rv.Type().ConvertibleTo(reflect.TypeFor[Object]()) {
+		return rv.Convert(reflect.TypeFor[Object]()).Interface().(Object), true
+	}
+	return nil, false
+}
+
+// asPlainArray returns v as an Array if it is an Array or a type based on
+// Array.
+func asPlainArray(v any) (Array, bool) {
+	if arr, ok := v.(Array); ok {
+		return arr, true
+	}
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Slice && rv.Type().ConvertibleTo(reflect.TypeFor[Array]()) {
+		return rv.Convert(reflect.TypeFor[Array]()).Interface().(Array), true
+	}
+	return nil, false
+}
+
+// FormatDiff renders changes for human consumption, one per line, e.g.
+//
+//	changed genres.0.name: "Action" -> "Adventure"
+//	added genres.1: {"id":12}
+func FormatDiff(changes []Change) string {
+	sb := strings.Builder{}
+	for _, c := range changes {
+		path := strings.Join(c.Path, ".")
+		if path == "" {
+			path = "(root)"
+		}
+		switch c.Kind {
+		case ChangeAdded:
+			sb.WriteString(fmt.Sprintf("%s %s: %s\n", c.Kind, path, formatValue(c.New)))
+		case ChangeRemoved:
+			sb.WriteString(fmt.Sprintf("%s %s: %s\n", c.Kind, path, formatValue(c.Old)))
+		default:
+			sb.WriteString(fmt.Sprintf("%s %s: %s -> %s\n", c.Kind, path, formatValue(c.Old), formatValue(c.New)))
+		}
+	}
+	return sb.String()
+}
+
+// formatValue renders v as compact JSON, falling back to %v for values that
+// cannot be marshaled.
+func formatValue(v any) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+	return string(data)
+}
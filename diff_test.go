@@ -0,0 +1,53 @@
+package jsonflex_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/krelinga/go-jsonflex"
+)
+
+func TestDiff(t *testing.T) {
+	old := Movie{
+		"title":  "Inception",
+		"id":     jsonflex.Number(1),
+		"adult":  false,
+		"genres": jsonflex.Array{jsonflex.Object{"id": jsonflex.Number(28), "name": "Action"}},
+	}
+	updated := jsonflex.Object{
+		"title": "Inception",
+		"id":    1,
+		"genres": jsonflex.Array{
+			jsonflex.Object{"id": jsonflex.Number(28), "name": "Adventure"},
+			jsonflex.Object{"id": jsonflex.Number(12)},
+		},
+		"runtime": jsonflex.Number(148),
+	}
+
+	expected := []jsonflex.Change{
+		{Path: []string{"adult"}, Kind: jsonflex.ChangeRemoved, Old: false},
+		{Path: []string{"genres", "0", "name"}, Kind: jsonflex.ChangeModified, Old: "Action", New: "Adventure"},
+		{Path: []string{"genres", "1"}, Kind: jsonflex.ChangeAdded, New: jsonflex.Object{"id": jsonflex.Number(12)}},
+		{Path: []string{"runtime"}, Kind: jsonflex.ChangeAdded, New: jsonflex.Number(148)},
+	}
+	got := jsonflex.Diff(old, updated)
+	if diff := cmp.Diff(expected, got); diff != "" {
+		t.Errorf("mismatch (-want +got):\n%s", diff)
+	}
+
+	expectedFormat := `removed adult: false
+changed genres.0.name: "Action" -> "Adventure"
+added genres.1: {"id":12}
+added runtime: 148
+`
+	if diff := cmp.Diff(expectedFormat, jsonflex.FormatDiff(got)); diff != "" {
+		t.Errorf("mismatch (-want +got):\n%s", diff)
+	}
+
+	if got := jsonflex.Diff(jsonflex.Array{"a"}, "a"); len(got) != 1 || got[0].Kind != jsonflex.ChangeModified || len(got[0].Path) != 0 {
+		t.Errorf("expected a single root change for mismatched types, got %v", got)
+	}
+	if got := jsonflex.Diff(old, old); got != nil {
+		t.Errorf("expected no changes for identical values, got %v", got)
+	}
+}
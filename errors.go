@@ -0,0 +1,116 @@
+package jsonflex
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// ConversionError describes a value that a Converter could not convert.
+// It exposes the expected and actual types for building precise validation
+// responses, and matches ErrCannotConvert via errors.Is.
+type ConversionError struct {
+	// Expected names the type the Converter was trying to produce.
+	Expected string
+	// ActualType is the dynamic type of Value, or nil if Value is nil.
+	ActualType reflect.Type
+	// Value is the input that could not be converted.
+	Value any
+	// Reason optionally explains why an otherwise well-typed value was rejected.
+	Reason string
+}
+
+func newConversionError(v any, expected string) *ConversionError {
+	return &ConversionError{Expected: expected, ActualType: reflect.TypeOf(v), Value: v}
+}
+
+func (e *ConversionError) Error() string {
+	actual := "<nil>"
+	if e.ActualType != nil {
+		actual = e.ActualType.String()
+	}
+	msg := fmt.Sprintf("%s %s to %s", ErrCannotConvert, actual, e.Expected)
+	if e.Reason != "" {
+		msg += ": " + e.Reason
+	}
+	return msg
+}
+
+// Unwrap returns ErrCannotConvert.
+func (e *ConversionError) Unwrap() error {
+	return ErrCannotConvert
+}
+
+// FieldError is a single failure within an ErrorList.
+type FieldError struct {
+	// Field locates the failure: an object key, or an array index in decimal.
+	Field string
+	// Err is the failure, whose message already names the location, e.g.
+	// "item 3: cannot convert bool to string".
+	Err error
+}
+
+func (e FieldError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns Err.
+func (e FieldError) Unwrap() error {
+	return e.Err
+}
+
+// ErrorList aggregates the failures reported by error-collecting functions
+// such as AsArrayCollect and DecodeStruct. Its message lists one failure per
+// line, and errors.Is and errors.As match against every member.
+type ErrorList struct {
+	Errors []FieldError
+	// Omitted counts further failures that were not stored, as by
+	// AsArrayCollectN.
+	Omitted int
+}
+
+func (l *ErrorList) Error() string {
+	lines := make([]string, 0, len(l.Errors)+1)
+	for _, e := range l.Errors {
+		lines = append(lines, e.Error())
+	}
+	if l.Omitted > 0 {
+		lines = append(lines, fmt.Sprintf("... and %d more (%d errors in total)", l.Omitted, len(l.Errors)+l.Omitted))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Unwrap returns the members of the list, for errors.Is and errors.As.
+func (l *ErrorList) Unwrap() []error {
+	errs := make([]error, len(l.Errors))
+	for i, e := range l.Errors {
+		errs[i] = e
+	}
+	return errs
+}
+
+// ByField returns the stored failures keyed by Field, which suits building
+// JSON validation responses. If several failures share a Field, the first
+// one is kept.
+func (l *ErrorList) ByField() map[string]error {
+	result := make(map[string]error, len(l.Errors))
+	for _, e := range l.Errors {
+		if _, exists := result[e.Field]; !exists {
+			result[e.Field] = e.Err
+		}
+	}
+	return result
+}
+
+// add records err for field.
+func (l *ErrorList) add(field string, err error) {
+	l.Errors = append(l.Errors, FieldError{Field: field, Err: err})
+}
+
+// orNil returns l, or nil if it holds no failures.
+func (l *ErrorList) orNil() error {
+	if len(l.Errors) == 0 && l.Omitted == 0 {
+		return nil
+	}
+	return l
+}
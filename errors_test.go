@@ -0,0 +1,97 @@
+package jsonflex_test
+
+import (
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/krelinga/go-jsonflex"
+)
+
+func TestConversionError(t *testing.T) {
+	cases := []struct {
+		name     string
+		conv     func(any) error
+		input    any
+		expected string
+		message  string
+	}{
+		{
+			name:     "String",
+			conv:     func(v any) error { _, err := jsonflex.AsString()(v); return err },
+			input:    true,
+			expected: "string",
+			message:  "cannot convert bool to string",
+		},
+		{
+			name:     "Int32 Range",
+			conv:     func(v any) error { _, err := jsonflex.AsInt32()(v); return err },
+			input:    jsonflex.Number(1.5),
+			expected: "int32",
+			message:  "cannot convert float64 to int32",
+		},
+		{
+			name:     "Int32 NaN",
+			conv:     func(v any) error { _, err := jsonflex.AsInt32()(v); return err },
+			input:    math.NaN(),
+			expected: "int32",
+			message:  "cannot convert float64 to int32: value is NaN",
+		},
+		{
+			name:     "Array",
+			conv:     func(v any) error { _, err := jsonflex.AsArray(jsonflex.AsAny())(v); return err },
+			input:    "x",
+			expected: "Array",
+			message:  "cannot convert string to Array",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := c.conv(c.input)
+			if !errors.Is(err, jsonflex.ErrCannotConvert) {
+				t.Fatalf("expected conversion error, got %v", err)
+			}
+			var ce *jsonflex.ConversionError
+			if !errors.As(err, &ce) {
+				t.Fatalf("expected *ConversionError, got %T", err)
+			}
+			if ce.Expected != c.expected || ce.ActualType != reflect.TypeOf(c.input) {
+				t.Errorf("expected %s from %T, got %s from %v", c.expected, c.input, ce.Expected, ce.ActualType)
+			}
+			if err.Error() != c.message {
+				t.Errorf("expected message %q, got %q", c.message, err.Error())
+			}
+		})
+	}
+}
+
+func TestErrorList(t *testing.T) {
+	_, err := jsonflex.AsArrayCollectN(2, jsonflex.AsString())(jsonflex.Array{"a", true, nil, jsonflex.Number(1)})
+	var list *jsonflex.ErrorList
+	if !errors.As(err, &list) {
+		t.Fatalf("expected an ErrorList, got %T: %v", err, err)
+	}
+	if len(list.Errors) != 2 || list.Omitted != 1 {
+		t.Errorf("expected 2 stored and 1 omitted errors, got %d and %d", len(list.Errors), list.Omitted)
+	}
+	if !errors.Is(err, jsonflex.ErrCannotConvert) || !errors.Is(err, jsonflex.ErrNullValue) {
+		t.Errorf("expected errors.Is to match every member, got %v", err)
+	}
+	var ce *jsonflex.ConversionError
+	if !errors.As(err, &ce) || ce.Expected != "string" {
+		t.Errorf("expected errors.As to find the ConversionError, got %v", err)
+	}
+	expected := "item 1: cannot convert bool to string (value: true)\nitem 2: null value (value: null)\n... and 1 more (3 errors in total)"
+	if err.Error() != expected {
+		t.Errorf("expected message %q, got %q", expected, err.Error())
+	}
+	byField := list.ByField()
+	if len(byField) != 2 || !errors.Is(byField["1"], jsonflex.ErrCannotConvert) || !errors.Is(byField["2"], jsonflex.ErrNullValue) {
+		t.Errorf("unexpected ByField result: %v", byField)
+	}
+
+	if _, err := jsonflex.AsArrayCollect(jsonflex.AsString())(jsonflex.Array{"a"}); err != nil {
+		t.Errorf("expected a nil error rather than an empty ErrorList, got %#v", err)
+	}
+}
@@ -0,0 +1,116 @@
+package jsonflex
+
+import (
+	"fmt"
+	"strings"
+)
+
+// GetFieldFallback is like GetField, but when key is absent from obj it calls
+// fallback and converts the value it provides instead.
+// The fallback is only consulted for truly missing fields; present fields that
+// are null or fail conversion are reported as usual. If fallback reports no
+// value either, the error wraps ErrFieldNotFound.
+// This is useful for layered configuration, e.g. falling back to an
+// environment variable or a defaults object.
+func GetFieldFallback[T any](obj Object, key string, conv Converter[T], fallback func() (any, bool)) (T, error) {
+	if obj == nil {
+		var zero T
+		return zero, fmt.Errorf("cannot access field %q on %w", key, ErrNilObject)
+	}
+	if _, exists := obj[key]; exists {
+		return GetField(obj, key, conv)
+	}
+	value, ok := fallback()
+	if !ok {
+		var zero T
+		return zero, fmt.Errorf("%w %q", ErrFieldNotFound, key)
+	}
+	result, err := conv(value)
+	if err != nil {
+		var zero T
+		return zero, fmt.Errorf("field %q: %w", key, err)
+	}
+	return result, nil
+}
+
+// GetCoalesce returns the conversion of the first of keys whose value in obj
+// is present and non-null, with conv applied as by GetField.
+// If every key is absent or null, the error wraps ErrFieldNotFound and lists
+// the keys that were tried. This suits APIs with redundant fields, such as
+// "thumbnail", "image", or "poster".
+func GetCoalesce[T any](obj Object, conv Converter[T], keys ...string) (T, error) {
+	var zero T
+	if obj == nil {
+		return zero, fmt.Errorf("cannot access fields %s on %w", quoteKeys(keys), ErrNilObject)
+	}
+	for _, key := range keys {
+		if value, exists := obj[key]; exists && value != nil {
+			return GetField(obj, key, conv)
+		}
+	}
+	return zero, fmt.Errorf("%w: none of %s is present and non-null", ErrFieldNotFound, quoteKeys(keys))
+}
+
+func quoteKeys(keys []string) string {
+	quoted := make([]string, len(keys))
+	for i, key := range keys {
+		quoted[i] = fmt.Sprintf("%q", key)
+	}
+	return strings.Join(quoted, ", ")
+}
+
+// GetFieldMapped is like GetField, but passes the converted value through fn,
+// saving a separate error check at the call site. An error from fn is
+// wrapped with the field key, just like a conversion error.
+func GetFieldMapped[T, U any](obj Object, key string, conv Converter[T], fn func(T) (U, error)) (U, error) {
+	value, err := GetField(obj, key, conv)
+	if err != nil {
+		var zero U
+		return zero, err
+	}
+	result, err := fn(value)
+	if err != nil {
+		var zero U
+		return zero, fmt.Errorf("field %q: %w", key, err)
+	}
+	return result, nil
+}
+
+// GetFieldOrZero is like GetField, but returns the zero value of T instead of
+// an error when the object is nil, the field is missing or null, or the
+// conversion fails.
+//
+// Because every failure is silently discarded, a zero result cannot be told
+// apart from a genuine zero value or from malformed input. Use it only for
+// best-effort output such as templates and log lines, never for validation.
+func GetFieldOrZero[T any](obj Object, key string, conv Converter[T]) T {
+	value, err := GetField(obj, key, conv)
+	if err != nil {
+		var zero T
+		return zero
+	}
+	return value
+}
+
+// GetFieldsConcat converts each of the array fields named by keys with conv,
+// as by GetField with AsArray(conv), and concatenates the results in the
+// order of keys, e.g. to combine "tags" and "categories" into one list.
+// Absent keys are skipped, but present values that are not arrays, including
+// null, are errors that name the key.
+func GetFieldsConcat[T any](obj Object, conv Converter[T], keys ...string) ([]T, error) {
+	if obj == nil {
+		return nil, fmt.Errorf("cannot access fields %s on %w", quoteKeys(keys), ErrNilObject)
+	}
+	result := []T{}
+	for _, key := range keys {
+		if _, exists := obj[key]; !exists {
+			continue
+		}
+		items, err := GetField(obj, key, AsArray(conv))
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, items...)
+	}
+	return result, nil
+}
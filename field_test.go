@@ -0,0 +1,169 @@
+package jsonflex_test
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/krelinga/go-jsonflex"
+)
+
+func TestGetFieldFallback(t *testing.T) {
+	config := jsonflex.Object{"host": "example.com", "port": "not a number"}
+	defaults := jsonflex.Object{"host": "localhost", "port": jsonflex.Number(8080), "debug": false}
+	fromDefaults := func(key string) func() (any, bool) {
+		return func() (any, bool) {
+			v, ok := defaults[key]
+			return v, ok
+		}
+	}
+
+	host, err := jsonflex.GetFieldFallback(config, "host", jsonflex.AsString(), fromDefaults("host"))
+	if err != nil || host != "example.com" {
+		t.Errorf("expected 'example.com', got %q with error %v", host, err)
+	}
+
+	debug, err := jsonflex.GetFieldFallback(config, "debug", jsonflex.AsBool(), fromDefaults("debug"))
+	if err != nil || debug {
+		t.Errorf("expected fallback value false, got %v with error %v", debug, err)
+	}
+
+	// Conversion failures do not consult the fallback.
+	_, err = jsonflex.GetFieldFallback(config, "port", jsonflex.AsInt32(), fromDefaults("port"))
+	if !errors.Is(err, jsonflex.ErrCannotConvert) {
+		t.Errorf("expected conversion error, got %v", err)
+	}
+
+	_, err = jsonflex.GetFieldFallback(config, "debug", jsonflex.AsString(), fromDefaults("debug"))
+	if !errors.Is(err, jsonflex.ErrCannotConvert) || !strings.Contains(err.Error(), `field "debug": `) {
+		t.Errorf("expected conversion error naming the key, got %v", err)
+	}
+
+	_, err = jsonflex.GetFieldFallback(config, "timeout", jsonflex.AsInt32(), fromDefaults("timeout"))
+	if !errors.Is(err, jsonflex.ErrFieldNotFound) {
+		t.Errorf("expected field not found error, got %v", err)
+	}
+}
+
+func TestGetFieldFallbackNilObject(t *testing.T) {
+	_, err := jsonflex.GetFieldFallback(nil, "host", jsonflex.AsString(), func() (any, bool) { return "localhost", true })
+	if !errors.Is(err, jsonflex.ErrNilObject) {
+		t.Errorf("expected nil object error, got %v", err)
+	}
+}
+
+func TestGetCoalesce(t *testing.T) {
+	obj := jsonflex.Object{"thumbnail": nil, "image": nil, "poster": "/poster.jpg", "backdrop": "/backdrop.jpg"}
+	got, err := jsonflex.GetCoalesce(obj, jsonflex.AsString(), "thumbnail", "image", "poster", "backdrop")
+	if err != nil || got != "/poster.jpg" {
+		t.Errorf("expected '/poster.jpg', got %q with error %v", got, err)
+	}
+
+	_, err = jsonflex.GetCoalesce(obj, jsonflex.AsString(), "thumbnail", "missing")
+	if !errors.Is(err, jsonflex.ErrFieldNotFound) {
+		t.Errorf("expected field not found error, got %v", err)
+	}
+
+	_, err = jsonflex.GetCoalesce(obj, jsonflex.AsBool(), "image", "poster")
+	if !errors.Is(err, jsonflex.ErrCannotConvert) {
+		t.Errorf("expected conversion error from first non-null field, got %v", err)
+	}
+
+	if _, err := jsonflex.GetCoalesce(nil, jsonflex.AsString(), "poster"); !errors.Is(err, jsonflex.ErrNilObject) {
+		t.Errorf("expected nil object error, got %v", err)
+	}
+}
+
+type Priority int
+
+const (
+	PriorityLow Priority = iota
+	PriorityHigh
+)
+
+func parsePriority(s string) (Priority, error) {
+	switch s {
+	case "low":
+		return PriorityLow, nil
+	case "high":
+		return PriorityHigh, nil
+	default:
+		return 0, fmt.Errorf("unknown priority %q", s)
+	}
+}
+
+func TestGetFieldMapped(t *testing.T) {
+	got, err := jsonflex.GetFieldMapped(jsonflex.Object{"priority": "high"}, "priority", jsonflex.AsString(), parsePriority)
+	if err != nil || got != PriorityHigh {
+		t.Errorf("expected high priority, got %v with error %v", got, err)
+	}
+
+	_, err = jsonflex.GetFieldMapped(jsonflex.Object{"priority": "urgent"}, "priority", jsonflex.AsString(), parsePriority)
+	if want := `field "priority": unknown priority "urgent"`; err == nil || err.Error() != want {
+		t.Errorf("expected error %q, got %v", want, err)
+	}
+	_, err = jsonflex.GetFieldMapped(jsonflex.Object{"priority": true}, "priority", jsonflex.AsString(), parsePriority)
+	if !errors.Is(err, jsonflex.ErrCannotConvert) || !strings.Contains(err.Error(), `field "priority"`) {
+		t.Errorf("expected conversion error naming the field, got %v", err)
+	}
+	_, err = jsonflex.GetFieldMapped(jsonflex.Object{}, "priority", jsonflex.AsString(), parsePriority)
+	if !errors.Is(err, jsonflex.ErrFieldNotFound) {
+		t.Errorf("expected field not found error, got %v", err)
+	}
+}
+
+func ExampleGetFieldMapped() {
+	task := jsonflex.Object{"priority": "high"}
+	priority, err := jsonflex.GetFieldMapped(task, "priority", jsonflex.AsString(), parsePriority)
+	fmt.Println(priority == PriorityHigh, err)
+	// Output:
+	// true <nil>
+}
+
+func TestGetFieldOrZero(t *testing.T) {
+	movie := jsonflex.Object{"title": "Inception", "id": "not a number", "tagline": nil}
+	if got := jsonflex.GetFieldOrZero(movie, "title", jsonflex.AsString()); got != "Inception" {
+		t.Errorf("expected Inception, got %q", got)
+	}
+	if got := jsonflex.GetFieldOrZero(movie, "id", jsonflex.AsInt32()); got != 0 {
+		t.Errorf("expected zero on conversion failure, got %d", got)
+	}
+	if got := jsonflex.GetFieldOrZero(movie, "tagline", jsonflex.AsString()); got != "" {
+		t.Errorf("expected zero on null, got %q", got)
+	}
+	if got := jsonflex.GetFieldOrZero(movie, "missing", jsonflex.AsArray(jsonflex.AsString())); got != nil {
+		t.Errorf("expected nil slice on missing field, got %v", got)
+	}
+	if got := jsonflex.GetFieldOrZero(nil, "title", jsonflex.AsString()); got != "" {
+		t.Errorf("expected zero on nil object, got %q", got)
+	}
+}
+
+func TestGetFieldsConcat(t *testing.T) {
+	post := jsonflex.Object{
+		"tags":       jsonflex.Array{"go", "json"},
+		"categories": jsonflex.Array{"programming"},
+		"title":      "Hello",
+	}
+	got, err := jsonflex.GetFieldsConcat(post, jsonflex.AsString(), "categories", "labels", "tags")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if diff := cmp.Diff([]string{"programming", "go", "json"}, got); diff != "" {
+		t.Errorf("mismatch (-want +got):\n%s", diff)
+	}
+
+	got, err = jsonflex.GetFieldsConcat(post, jsonflex.AsString(), "labels")
+	if err != nil || got == nil || len(got) != 0 {
+		t.Errorf("expected an empty slice, got %#v with error %v", got, err)
+	}
+	_, err = jsonflex.GetFieldsConcat(post, jsonflex.AsString(), "tags", "title")
+	if !errors.Is(err, jsonflex.ErrCannotConvert) || !strings.Contains(err.Error(), `field "title"`) {
+		t.Errorf("expected conversion error naming title, got %v", err)
+	}
+	if _, err := jsonflex.GetFieldsConcat(nil, jsonflex.AsString(), "tags"); !errors.Is(err, jsonflex.ErrNilObject) {
+		t.Errorf("expected nil object error, got %v", err)
+	}
+}
@@ -1,12 +1,21 @@
 // Package jsonflex provides utilities for working with JSON data in a flexible,
 // type-safe manner. It offers type conversion functions and utilities for
 // extracting values from JSON objects and arrays with proper error handling.
+//
+// Numeric converters such as AsFloat64 and AsInt32 accept numbers in every
+// form they commonly take: the float64 produced by encoding/json, the
+// json.Number and int64 values that Parse can produce, and any Go integer or
+// float kind, so a hand-built Object{"id": 5} works without wrapping 5 in
+// Number.
 package jsonflex
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"math"
+	"reflect"
+	"strconv"
 )
 
 // Object represents a JSON object as a map with string keys and any values.
@@ -27,24 +36,100 @@ type Number = float64
 type Converter[T any] func(any) (T, error)
 
 var (
-	ErrFieldNotFound = errors.New("field not found")
-	ErrCannotConvert = errors.New("cannot convert")
-	ErrNullValue     = errors.New("null value")
+	ErrFieldNotFound   = errors.New("field not found")
+	ErrCannotConvert   = errors.New("cannot convert")
+	ErrNullValue       = errors.New("null value")
+	ErrDuplicateKey    = errors.New("duplicate key")
+	ErrNotFound        = errors.New("no matching element")
+	ErrNilObject       = errors.New("nil object")
+	ErrTooManyElements = errors.New("too many elements")
+	ErrTimeout         = errors.New("conversion timed out")
+	ErrStop            = errors.New("stop")
 )
 
-// AsFloat64 returns a Converter that converts a value to float64.
-// It accepts float64 values and returns an error for nil or other types.
-// This is the primary converter for JSON numbers.
-func AsFloat64() Converter[float64] {
-	return func(v any) (float64, error) {
-		if v == nil {
-			return 0, ErrNullValue
+// normalizeNumber converts any Go numeric value, or a json.Number, to float64.
+// Values decoded by encoding/json are usually float64, but Objects built by
+// hand in Go often hold int, int64, float32, etc., and Parse may produce
+// int64 or json.Number depending on its NumberMode.
+func normalizeNumber(v any) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case json.Number:
+		f, err := n.Float64()
+		return f, err == nil
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(rv.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(rv.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return rv.Float(), true
+	}
+	return 0, false
+}
+
+// exactInt64 returns v as an int64 without going through float64 when v is
+// a Go integer or a json.Number holding an integer literal, so values beyond
+// 2^53 keep every digit. It returns false for anything else, including
+// integers outside the int64 range, which callers should handle with
+// normalizeNumber.
+func exactInt64(v any) (int64, bool) {
+	if n, ok := v.(json.Number); ok {
+		i, err := n.Int64()
+		return i, err == nil
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return rv.Int(), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if u := rv.Uint(); u <= math.MaxInt64 {
+			return int64(u), true
 		}
-		if f, ok := v.(float64); ok {
-			return f, nil
+	}
+	return 0, false
+}
+
+// exactUint64 is like exactInt64, but for the uint64 range.
+func exactUint64(v any) (uint64, bool) {
+	if n, ok := v.(json.Number); ok {
+		u, err := strconv.ParseUint(n.String(), 10, 64)
+		return u, err == nil
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if i := rv.Int(); i >= 0 {
+			return uint64(i), true
 		}
-		return 0, fmt.Errorf("%w %T to float64", ErrCannotConvert, v)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return rv.Uint(), true
+	}
+	return 0, false
+}
+
+// Float64 converts a value to float64.
+// It accepts float64 values as well as Go's native integer and float kinds,
+// and returns an error for nil or other types.
+// Float64 is the direct form of AsFloat64 for performance-critical code that
+// does not need a Converter value.
+func Float64(v any) (float64, error) {
+	if v == nil {
+		return 0, ErrNullValue
 	}
+	if f, ok := normalizeNumber(v); ok {
+		return f, nil
+	}
+	return 0, newConversionError(v, "float64")
+}
+
+// AsFloat64 returns a Converter that converts a value to float64 using Float64.
+// This is the primary converter for JSON numbers.
+func AsFloat64() Converter[float64] {
+	return Float64
 }
 
 // AsString returns a Converter that converts a value to string.
@@ -58,40 +143,54 @@ func AsString() Converter[string] {
 		if s, ok := v.(string); ok {
 			return s, nil
 		}
-		return "", fmt.Errorf("%w %T to string", ErrCannotConvert, v)
+		return "", newConversionError(v, "string")
 	}
 }
 
-// AsBool returns a Converter that converts a value to bool.
+// Bool converts a value to bool.
 // It accepts bool values and returns an error for nil or other types.
+// Bool is the direct form of AsBool.
+func Bool(v any) (bool, error) {
+	if v == nil {
+		return false, ErrNullValue
+	}
+	if b, ok := v.(bool); ok {
+		return b, nil
+	}
+	return false, newConversionError(v, "bool")
+}
+
+// AsBool returns a Converter that converts a value to bool using Bool.
 // This converter is used for extracting JSON boolean values.
 func AsBool() Converter[bool] {
-	return func(v any) (bool, error) {
-		if v == nil {
-			return false, ErrNullValue
-		}
-		if b, ok := v.(bool); ok {
-			return b, nil
-		}
-		return false, fmt.Errorf("%w %T to bool", ErrCannotConvert, v)
-	}
+	return Bool
 }
 
-// AsInt32 returns a Converter that converts a value to int32.
-// It first converts the value to float64 using AsFloat64, then checks if the
+// Int32 converts a value to int32.
+// It first converts the value to float64 using Float64, then checks if the
 // result can be safely converted to int32 without loss of precision.
-// The value must be within the int32 range and be a whole number.
-func AsInt32() Converter[int32] {
-	return func(v any) (int32, error) {
-		f, err := AsFloat64()(v)
-		if err != nil {
-			return 0, err
-		}
-		if f >= float64(math.MinInt32) && f <= float64(math.MaxInt32) && f == float64(int32(f)) {
-			return int32(f), nil
-		}
-		return 0, fmt.Errorf("%w %T to int32", ErrCannotConvert, v)
+// The value must be within the int32 range and be a whole number; NaN is
+// rejected with a dedicated error message.
+// Int32 is the direct form of AsInt32.
+func Int32(v any) (int32, error) {
+	f, err := Float64(v)
+	if err != nil {
+		return 0, err
+	}
+	if math.IsNaN(f) {
+		err := newConversionError(v, "int32")
+		err.Reason = "value is NaN"
+		return 0, err
+	}
+	if f >= float64(math.MinInt32) && f <= float64(math.MaxInt32) && f == float64(int32(f)) {
+		return int32(f), nil
 	}
+	return 0, newConversionError(v, "int32")
+}
+
+// AsInt32 returns a Converter that converts a value to int32 using Int32.
+func AsInt32() Converter[int32] {
+	return Int32
 }
 
 // AsObject returns a Converter that converts a value to a type T that is based on Object.
@@ -105,7 +204,7 @@ func AsObject[T ~Object]() Converter[T] {
 		}
 		obj, ok := v.(Object)
 		if !ok {
-			return T{}, fmt.Errorf("%w %T to Object", ErrCannotConvert, v)
+			return T{}, newConversionError(v, "Object")
 		}
 		return T(obj), nil
 	}
@@ -115,6 +214,8 @@ func AsObject[T ~Object]() Converter[T] {
 // It takes a valueConv Converter[T] to convert each element of the array.
 // The function accepts Array values ([]any) and applies the valueConv to each element,
 // returning an error if the input is not an array or if any element conversion fails.
+// Element errors name the index and include a short, truncated rendering of the
+// offending value.
 // This enables type-safe extraction of homogeneous arrays from JSON data.
 func AsArray[T any](valueConv Converter[T]) Converter[[]T] {
 	return func(v any) ([]T, error) {
@@ -123,13 +224,13 @@ func AsArray[T any](valueConv Converter[T]) Converter[[]T] {
 		}
 		arr, ok := v.([]any)
 		if !ok {
-			return nil, fmt.Errorf("%w %T to Array", ErrCannotConvert, v)
+			return nil, newConversionError(v, "Array")
 		}
 		result := make([]T, len(arr))
 		for i, item := range arr {
 			converted, err := valueConv(item)
 			if err != nil {
-				return nil, fmt.Errorf("item %d: %w", i, err)
+				return nil, itemError(i, item, err)
 			}
 			result[i] = converted
 		}
@@ -149,19 +250,28 @@ func AsAny() Converter[any] {
 
 // GetField extracts a field from an Object and converts it to type T using the provided Converter.
 // It takes an Object, a field key, and a Converter[T] to apply to the field value.
-// Returns an error if the object is nil, the field doesn't exist, or the conversion fails.
+// Returns an error if the object is nil (ErrNilObject), the field doesn't exist
+// (ErrFieldNotFound), or the conversion fails.
+// Conversion errors are wrapped with the field key, as in
+// `field "adult": cannot convert string to bool`, so errors.Is and errors.As
+// still match the converter's sentinels.
 // This is the primary function for type-safe field extraction from JSON objects.
 func GetField[T any](obj Object, key string, conv Converter[T]) (T, error) {
 	if obj == nil {
 		var zero T
-		return zero, fmt.Errorf("cannot access field %q on nil object", key)
+		return zero, fmt.Errorf("cannot access field %q on %w", key, ErrNilObject)
 	}
 	value, exists := obj[key]
 	if !exists {
 		var zero T
 		return zero, fmt.Errorf("%w %q", ErrFieldNotFound, key)
 	}
-	return conv(value)
+	result, err := conv(value)
+	if err != nil {
+		var zero T
+		return zero, fmt.Errorf("field %q: %w", key, err)
+	}
+	return result, nil
 }
 
 // FromArray converts an Array to a slice of type T using the provided Converter.
@@ -172,3 +282,9 @@ func GetField[T any](obj Object, key string, conv Converter[T]) (T, error) {
 func FromArray[T any](arr Array, conv Converter[T]) ([]T, error) {
 	return AsArray(conv)(arr)
 }
+
+// itemError annotates err, the failure to convert the array element v at index
+// i, with the index and a short rendering of the offending value.
+func itemError(i int, v any, err error) error {
+	return fmt.Errorf("item %d: %w (value: %s)", i, err, truncate(formatValue(v), 40))
+}
@@ -2,6 +2,7 @@ package jsonflex_test
 
 import (
 	"errors"
+	"strings"
 	"testing"
 
 	"github.com/krelinga/go-jsonflex"
@@ -120,8 +121,8 @@ func TestBar(t *testing.T) {
 func TestErrors(t *testing.T) {
 	// Test nil object access
 	_, err := jsonflex.GetField(nil, "title", jsonflex.AsString())
-	if err == nil || errors.Is(err, jsonflex.ErrFieldNotFound) || errors.Is(err, jsonflex.ErrCannotConvert) {
-		t.Errorf("expected non-specific error accessing field on nil object, got %v", err)
+	if !errors.Is(err, jsonflex.ErrNilObject) || errors.Is(err, jsonflex.ErrFieldNotFound) || errors.Is(err, jsonflex.ErrCannotConvert) {
+		t.Errorf("expected only nil object error accessing field on nil object, got %v", err)
 	}
 
 	// Test field not found
@@ -136,6 +137,9 @@ func TestErrors(t *testing.T) {
 	if err == nil || !errors.Is(err, jsonflex.ErrCannotConvert) {
 		t.Errorf("expected conversion error, got %v", err)
 	}
+	if want := `field "title": cannot convert string to bool`; err == nil || err.Error() != want {
+		t.Errorf("expected error %q, got %v", want, err)
+	}
 
 	// Test nil value
 	_, err = jsonflex.GetField(movie, "alt_title", jsonflex.AsString())
@@ -143,3 +147,126 @@ func TestErrors(t *testing.T) {
 		t.Errorf("expected null value error, got %v", err)
 	}
 }
+
+func TestNativeNumbers(t *testing.T) {
+	movie := Movie{
+		"id":        12345,
+		"genre_ids": jsonflex.Array{int64(28), int32(12), uint16(878)},
+	}
+	id, err := movie.ID()
+	if err != nil || id != 12345 {
+		t.Errorf("expected id 12345, got %d with error %v", id, err)
+	}
+	genreIDs, err := movie.GenreIDs()
+	if err != nil || len(genreIDs) != 3 || genreIDs[0] != 28 || genreIDs[1] != 12 || genreIDs[2] != 878 {
+		t.Errorf("expected genre_ids [28, 12, 878], got %v with error %v", genreIDs, err)
+	}
+
+	f, err := jsonflex.AsFloat64()(float32(1.5))
+	if err != nil || f != 1.5 {
+		t.Errorf("expected 1.5, got %v with error %v", f, err)
+	}
+
+	// Precision and range checks still apply.
+	if _, err := jsonflex.AsInt32()(float32(1.5)); !errors.Is(err, jsonflex.ErrCannotConvert) {
+		t.Errorf("expected conversion error for fractional float32, got %v", err)
+	}
+	if _, err := jsonflex.AsInt32()(int64(1) << 40); !errors.Is(err, jsonflex.ErrCannotConvert) {
+		t.Errorf("expected conversion error for out-of-range int64, got %v", err)
+	}
+	if _, err := jsonflex.AsFloat64()("1"); !errors.Is(err, jsonflex.ErrCannotConvert) {
+		t.Errorf("expected conversion error for string, got %v", err)
+	}
+}
+
+func TestDirectConverters(t *testing.T) {
+	if f, err := jsonflex.Float64(jsonflex.Number(1.5)); err != nil || f != 1.5 {
+		t.Errorf("expected 1.5, got %v with error %v", f, err)
+	}
+	if i, err := jsonflex.Int32(jsonflex.Number(7)); err != nil || i != 7 {
+		t.Errorf("expected 7, got %d with error %v", i, err)
+	}
+	if b, err := jsonflex.Bool(true); err != nil || !b {
+		t.Errorf("expected true, got %v with error %v", b, err)
+	}
+	if _, err := jsonflex.Int32("7"); !errors.Is(err, jsonflex.ErrCannotConvert) {
+		t.Errorf("expected conversion error, got %v", err)
+	}
+	if _, err := jsonflex.Bool(nil); !errors.Is(err, jsonflex.ErrNullValue) {
+		t.Errorf("expected null value error, got %v", err)
+	}
+}
+
+func benchmarkNumbers() jsonflex.Array {
+	arr := make(jsonflex.Array, 100)
+	for i := range arr {
+		arr[i] = jsonflex.Number(i)
+	}
+	return arr
+}
+
+// BenchmarkAsInt32 and BenchmarkInt32 do the same work, so together they
+// measure the cost of going through the AsInt32 constructor.
+func BenchmarkAsInt32(b *testing.B) {
+	arr := benchmarkNumbers()
+	b.ReportAllocs()
+	for b.Loop() {
+		for _, v := range arr {
+			if _, err := jsonflex.AsInt32()(v); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
+
+func BenchmarkInt32(b *testing.B) {
+	arr := benchmarkNumbers()
+	b.ReportAllocs()
+	for b.Loop() {
+		for _, v := range arr {
+			if _, err := jsonflex.Int32(v); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
+
+// BenchmarkAsArrayInt32 and BenchmarkInt32Slice both build the result slice,
+// so together they measure the overhead of AsArray itself.
+func BenchmarkAsArrayInt32(b *testing.B) {
+	arr := benchmarkNumbers()
+	b.ReportAllocs()
+	for b.Loop() {
+		if _, err := jsonflex.AsArray(jsonflex.AsInt32())(arr); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkInt32Slice(b *testing.B) {
+	arr := benchmarkNumbers()
+	b.ReportAllocs()
+	for b.Loop() {
+		result := make([]int32, len(arr))
+		for i, v := range arr {
+			n, err := jsonflex.Int32(v)
+			if err != nil {
+				b.Fatal(err)
+			}
+			result[i] = n
+		}
+	}
+}
+
+func TestArrayItemErrorValue(t *testing.T) {
+	_, err := jsonflex.AsArray(jsonflex.AsInt32())(jsonflex.Array{jsonflex.Number(1), "two"})
+	if !errors.Is(err, jsonflex.ErrCannotConvert) || err.Error() != `item 1: cannot convert string to float64 (value: "two")` {
+		t.Errorf("expected error with value snippet, got %v", err)
+	}
+
+	long := jsonflex.Object{"description": strings.Repeat("x", 100)}
+	_, err = jsonflex.AsArray(jsonflex.AsString())(jsonflex.Array{long})
+	if err == nil || !strings.Contains(err.Error(), `(value: {"description":"xxxxxxxxxxxxxxxxxxxxxxxx...)`) {
+		t.Errorf("expected truncated value snippet, got %v", err)
+	}
+}
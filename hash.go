@@ -0,0 +1,110 @@
+package jsonflex
+
+import (
+	"encoding/binary"
+	"hash"
+	"hash/fnv"
+	"maps"
+	"math"
+	"reflect"
+	"slices"
+)
+
+// Hash returns a 64-bit FNV-1a digest of v that depends only on its JSON
+// content, so it is suitable as a cache key or for deduplicating decoded
+// documents. Values that are Equal always hash identically.
+//
+// The digest is computed over a canonical encoding of v:
+//   - Objects, including types based on Object, are encoded with their keys
+//     in sorted byte order, so map iteration order does not matter.
+//   - Arrays, including types based on Array, are encoded in element order.
+//   - Numbers of any Go numeric type are encoded as their float64 value, so
+//     int 5 and float64 5 hash the same. Negative zero is treated as zero and
+//     every NaN is treated as the same value.
+//   - Strings (of any string kind), booleans and nil are encoded with a
+//     distinct type tag, so "1", 1 and true never collide by construction.
+//
+// Values that cannot appear in decoded JSON, such as channels or functions,
+// are rejected with ErrCannotConvert. The encoding is fixed, so digests are
+// stable across processes and releases.
+func Hash(v any) (uint64, error) {
+	h := fnv.New64a()
+	if err := writeCanonical(h, v); err != nil {
+		return 0, err
+	}
+	return h.Sum64(), nil
+}
+
+// Type tags for the canonical encoding used by Hash.
+const (
+	hashNull byte = iota
+	hashFalse
+	hashTrue
+	hashNumber
+	hashString
+	hashArray
+	hashObject
+)
+
+func writeCanonical(h hash.Hash64, v any) error {
+	if v == nil {
+		h.Write([]byte{hashNull})
+		return nil
+	}
+	if obj, ok := asPlainObject(v); ok {
+		h.Write([]byte{hashObject})
+		writeLength(h, len(obj))
+		for _, key := range slices.Sorted(maps.Keys(obj)) {
+			writeString(h, key)
+			if err := writeCanonical(h, obj[key]); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if arr, ok := asPlainArray(v); ok {
+		h.Write([]byte{hashArray})
+		writeLength(h, len(arr))
+		for _, item := range arr {
+			if err := writeCanonical(h, item); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if f, ok := normalizeNumber(v); ok {
+		switch {
+		case f == 0:
+			f = 0
+		case math.IsNaN(f):
+			f = math.NaN()
+		}
+		h.Write([]byte{hashNumber})
+		h.Write(binary.BigEndian.AppendUint64(nil, math.Float64bits(f)))
+		return nil
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Bool:
+		if rv.Bool() {
+			h.Write([]byte{hashTrue})
+		} else {
+			h.Write([]byte{hashFalse})
+		}
+	case reflect.String:
+		h.Write([]byte{hashString})
+		writeString(h, rv.String())
+	default:
+		return newConversionError(v, "hashable JSON value")
+	}
+	return nil
+}
+
+func writeLength(h hash.Hash64, n int) {
+	h.Write(binary.BigEndian.AppendUint64(nil, uint64(n)))
+}
+
+func writeString(h hash.Hash64, s string) {
+	writeLength(h, len(s))
+	h.Write([]byte(s))
+}
@@ -0,0 +1,72 @@
+package jsonflex_test
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/krelinga/go-jsonflex"
+)
+
+func TestHash(t *testing.T) {
+	hash := func(v any) uint64 {
+		t.Helper()
+		h, err := jsonflex.Hash(v)
+		if err != nil {
+			t.Fatalf("unexpected error hashing %#v: %v", v, err)
+		}
+		return h
+	}
+
+	equalPairs := []struct {
+		name string
+		a, b any
+	}{
+		{
+			name: "key order",
+			a:    jsonflex.Object{"a": jsonflex.Number(1), "b": jsonflex.Array{"x", true, nil}},
+			b:    jsonflex.Object{"b": jsonflex.Array{"x", true, nil}, "a": jsonflex.Number(1)},
+		},
+		{name: "number types", a: jsonflex.Array{5, int64(6)}, b: jsonflex.Array{jsonflex.Number(5), float32(6)}},
+		{name: "negative zero", a: math.Copysign(0, -1), b: jsonflex.Number(0)},
+		{name: "named object", a: Movie{"title": "Inception"}, b: jsonflex.Object{"title": "Inception"}},
+	}
+	for _, c := range equalPairs {
+		if !jsonflex.Equal(c.a, c.b) {
+			t.Errorf("%s: expected values to be Equal", c.name)
+		}
+		if hash(c.a) != hash(c.b) {
+			t.Errorf("%s: expected equal hashes", c.name)
+		}
+	}
+
+	distinct := []any{
+		nil,
+		true,
+		false,
+		"1",
+		jsonflex.Number(1),
+		jsonflex.Array{},
+		jsonflex.Object{},
+		jsonflex.Array{"ab"},
+		jsonflex.Array{"a", "b"},
+		jsonflex.Object{"a": "b"},
+		jsonflex.Object{"ab": nil},
+	}
+	seen := map[uint64]any{}
+	for _, v := range distinct {
+		h := hash(v)
+		if prev, ok := seen[h]; ok {
+			t.Errorf("expected distinct hashes for %#v and %#v", prev, v)
+		}
+		seen[h] = v
+	}
+
+	if hash(jsonflex.Object{"a": jsonflex.Number(1)}) != hash(jsonflex.Object{"a": jsonflex.Number(1)}) {
+		t.Error("expected hash to be deterministic")
+	}
+
+	if _, err := jsonflex.Hash(jsonflex.Object{"f": func() {}}); !errors.Is(err, jsonflex.ErrCannotConvert) {
+		t.Errorf("expected conversion error, got %v", err)
+	}
+}
@@ -0,0 +1,247 @@
+package jsonflex
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+)
+
+// ParseArrayStream reads a JSON array from r one element at a time, converting
+// each with conv and passing it to fn, so only a single element is held in
+// memory at once.
+// Elements are decoded the same way encoding/json decodes into an any value,
+// so numbers arrive as float64 and objects as Object. Processing stops at the
+// first decoding, conversion, or fn error. Data after the closing bracket is
+// not read.
+func ParseArrayStream[T any](r io.Reader, conv Converter[T], fn func(i int, v T) error) error {
+	dec := json.NewDecoder(r)
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if delim, ok := tok.(json.Delim); !ok || delim != '[' {
+		ce := newConversionError(tok, "Array")
+		ce.Reason = fmt.Sprintf("input starts with %v", tok)
+		return ce
+	}
+	for i := 0; dec.More(); i++ {
+		var item any
+		if err := dec.Decode(&item); err != nil {
+			return fmt.Errorf("item %d: %w", i, err)
+		}
+		converted, err := conv(item)
+		if err != nil {
+			return itemError(i, item, err)
+		}
+		if err := fn(i, converted); err != nil {
+			return err
+		}
+	}
+	if _, err := dec.Token(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// WriteLines writes arr to w as newline-delimited JSON (NDJSON): each element
+// is marshaled on its own line, and every line, including the last, ends with
+// a newline. An empty array writes nothing. Object keys are emitted in sorted
+// order, as by encoding/json, so output is deterministic.
+func WriteLines(w io.Writer, arr Array) error {
+	enc := json.NewEncoder(w)
+	for i, item := range arr {
+		if err := enc.Encode(item); err != nil {
+			return fmt.Errorf("item %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// MarshalLines is like WriteLines, but returns the NDJSON output as bytes.
+func MarshalLines(arr Array) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := WriteLines(&buf, arr); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// AsEmbeddedJSON returns a Converter for double-encoded JSON, i.e. a string
+// field whose content is itself a JSON document such as "{\"a\":1}".
+// It converts the value with AsString, decodes the string as JSON (numbers
+// become float64 and objects Object, as elsewhere in the package), and then
+// applies conv to the decoded value. Strings that are not valid JSON are
+// rejected with ErrCannotConvert, quoting a prefix of the string.
+func AsEmbeddedJSON[T any](conv Converter[T]) Converter[T] {
+	return func(v any) (T, error) {
+		var zero T
+		s, err := AsString()(v)
+		if err != nil {
+			return zero, err
+		}
+		var decoded any
+		if err := json.Unmarshal([]byte(s), &decoded); err != nil {
+			ce := newConversionError(v, "embedded JSON")
+			ce.Reason = fmt.Sprintf("%q: %v", truncate(s, 32), err)
+			return zero, ce
+		}
+		return conv(decoded)
+	}
+}
+
+// truncate shortens s to at most n runes, marking any cut with "...".
+func truncate(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n]) + "..."
+}
+
+// GetFieldBytes returns the value of field key in obj marshaled as JSON, for
+// forwarding a subtree to another service without typed conversion.
+// The value is re-marshaled rather than preserved byte-for-byte from the
+// original input: whitespace is dropped and object keys are emitted in
+// sorted order. Returns an error wrapping ErrFieldNotFound if key is absent.
+func GetFieldBytes(obj Object, key string) ([]byte, error) {
+	return GetField(obj, key, json.Marshal)
+}
+
+// decodeRaw decodes v if it is undecoded JSON (json.RawMessage or []byte),
+// and returns any other value unchanged.
+func decodeRaw(v any) (any, error) {
+	var data []byte
+	switch raw := v.(type) {
+	case json.RawMessage:
+		data = raw
+	case []byte:
+		data = raw
+	default:
+		return v, nil
+	}
+	var decoded any
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		ce := newConversionError(v, "decoded JSON")
+		ce.Reason = fmt.Sprintf("%q: %v", truncate(string(data), 32), err)
+		return nil, ce
+	}
+	return decoded, nil
+}
+
+// AsObjectFromRaw is like AsObject, but also accepts undecoded JSON in a
+// json.RawMessage or []byte, as produced by deferred decoding, and decodes it
+// before converting. Already-decoded Objects are accepted as well. It is a
+// separate converter so that AsObject never misinterprets ordinary []byte
+// values.
+func AsObjectFromRaw[T ~Object]() Converter[T] {
+	return func(v any) (T, error) {
+		decoded, err := decodeRaw(v)
+		if err != nil {
+			return nil, err
+		}
+		return AsObject[T]()(decoded)
+	}
+}
+
+// AsArrayFromRaw is like AsArray, but also accepts undecoded JSON in a
+// json.RawMessage or []byte, as AsObjectFromRaw does for objects.
+func AsArrayFromRaw[T any](valueConv Converter[T]) Converter[[]T] {
+	return func(v any) ([]T, error) {
+		decoded, err := decodeRaw(v)
+		if err != nil {
+			return nil, err
+		}
+		return AsArray(valueConv)(decoded)
+	}
+}
+
+// NumberMode controls how Parse represents JSON numbers.
+type NumberMode int
+
+const (
+	// NumberModeFloat64 decodes every number as a float64, as encoding/json
+	// does by default.
+	NumberModeFloat64 NumberMode = iota
+	// NumberModeJSON keeps every number as a json.Number holding its original
+	// text, so no precision is lost at the decode boundary.
+	NumberModeJSON
+	// NumberModeIntOrFloat decodes integer literals that fit in an int64, and
+	// integral literals such as 1e3 below 2^53, as int64, and all other
+	// numbers as float64.
+	NumberModeIntOrFloat
+)
+
+// ParseOption configures Parse.
+type ParseOption func(*parseConfig)
+
+type parseConfig struct {
+	numberMode NumberMode
+}
+
+// WithNumberMode sets how Parse represents numbers. The default is
+// NumberModeFloat64.
+func WithNumberMode(mode NumberMode) ParseOption {
+	return func(c *parseConfig) {
+		c.numberMode = mode
+	}
+}
+
+// Parse decodes a single JSON value from data into the representation used by
+// this package: Object for objects, Array for arrays, and string, bool or nil
+// for the other scalars. Numbers are represented according to WithNumberMode;
+// converters such as AsFloat64 and AsInt32 accept all of the representations.
+// Data after the value, other than whitespace, is an error.
+func Parse(data []byte, opts ...ParseOption) (any, error) {
+	var config parseConfig
+	for _, opt := range opts {
+		opt(&config)
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if config.numberMode != NumberModeFloat64 {
+		dec.UseNumber()
+	}
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+	if _, err := dec.Token(); !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("unexpected data after JSON value at offset %d", dec.InputOffset())
+	}
+	if config.numberMode == NumberModeIntOrFloat {
+		v = intOrFloat(v)
+	}
+	return v, nil
+}
+
+// intOrFloat replaces every json.Number in v, in place, with an int64 if it
+// is integral and converts exactly, or a float64 otherwise.
+func intOrFloat(v any) any {
+	switch val := v.(type) {
+	case Object:
+		for key, item := range val {
+			val[key] = intOrFloat(item)
+		}
+	case Array:
+		for i, item := range val {
+			val[i] = intOrFloat(item)
+		}
+	case json.Number:
+		if n, err := val.Int64(); err == nil {
+			return n
+		}
+		// The decoder only produces valid numbers, but ones too large for a
+		// float64 still report a range error alongside ±Inf. Literals such as
+		// 1e3 or 2.0 only become int64 below 2^53, where the float64 is
+		// certain to hold their exact value; 9007199254740993.0 would
+		// otherwise round to a different integer.
+		f, _ := val.Float64()
+		if f == math.Trunc(f) && math.Abs(f) < 1<<53 {
+			return int64(f)
+		}
+		return f
+	}
+	return v
+}
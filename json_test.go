@@ -0,0 +1,274 @@
+package jsonflex_test
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/krelinga/go-jsonflex"
+)
+
+func TestParseArrayStream(t *testing.T) {
+	input := `[{"id": 1, "name": "Action"}, {"id": 2, "name": "Adventure"}]`
+	var names []string
+	var ids []int32
+	err := jsonflex.ParseArrayStream(strings.NewReader(input), jsonflex.AsObject[Genre](), func(i int, g Genre) error {
+		ids = append(ids, assertNoError(g.ID())(t))
+		names = append(names, assertNoError(g.Name())(t))
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if diff := cmp.Diff([]int32{1, 2}, ids); diff != "" {
+		t.Errorf("mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]string{"Action", "Adventure"}, names); diff != "" {
+		t.Errorf("mismatch (-want +got):\n%s", diff)
+	}
+
+	noop := func(int, int32) error { return nil }
+	err = jsonflex.ParseArrayStream(strings.NewReader(`[1, "x"]`), jsonflex.AsInt32(), noop)
+	if !errors.Is(err, jsonflex.ErrCannotConvert) || !strings.Contains(err.Error(), "item 1") {
+		t.Errorf("expected conversion error naming item 1, got %v", err)
+	}
+	err = jsonflex.ParseArrayStream(strings.NewReader(`{"a": 1}`), jsonflex.AsInt32(), noop)
+	if !errors.Is(err, jsonflex.ErrCannotConvert) {
+		t.Errorf("expected conversion error for non-array input, got %v", err)
+	}
+	if err := jsonflex.ParseArrayStream(strings.NewReader(`[1, 2`), jsonflex.AsInt32(), noop); err == nil {
+		t.Error("expected error for truncated input")
+	}
+}
+
+func TestMarshalLines(t *testing.T) {
+	arr := jsonflex.Array{
+		jsonflex.Object{"name": "Action", "id": jsonflex.Number(28)},
+		"plain",
+		jsonflex.Array{jsonflex.Number(1), nil},
+	}
+	got, err := jsonflex.MarshalLines(arr)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := `{"id":28,"name":"Action"}
+"plain"
+[1,null]
+`
+	if diff := cmp.Diff(expected, string(got)); diff != "" {
+		t.Errorf("mismatch (-want +got):\n%s", diff)
+	}
+
+	got, err = jsonflex.MarshalLines(jsonflex.Array{})
+	if err != nil || len(got) != 0 {
+		t.Errorf("expected empty output, got %q with error %v", got, err)
+	}
+
+	var sb strings.Builder
+	err = jsonflex.WriteLines(&sb, jsonflex.Array{"ok", func() {}})
+	if err == nil || !strings.Contains(err.Error(), "item 1") {
+		t.Errorf("expected marshal error naming item 1, got %v", err)
+	}
+}
+
+func TestAsEmbeddedJSON(t *testing.T) {
+	obj := jsonflex.Object{"payload": `{"id": 28, "name": "Action"}`}
+	genre, err := jsonflex.GetField(obj, "payload", jsonflex.AsEmbeddedJSON(jsonflex.AsObject[Genre]()))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if assertNoError(genre.ID())(t) != 28 || assertNoError(genre.Name())(t) != "Action" {
+		t.Errorf("unexpected decoded genre: %v", genre)
+	}
+
+	ids, err := jsonflex.AsEmbeddedJSON(jsonflex.AsArray(jsonflex.AsInt32()))("[1, 2]")
+	if err != nil || len(ids) != 2 || ids[1] != 2 {
+		t.Errorf("expected [1 2], got %v with error %v", ids, err)
+	}
+
+	_, err = jsonflex.AsEmbeddedJSON(jsonflex.AsAny())(`{"truncated": "this payload is long enough to be cut`)
+	if !errors.Is(err, jsonflex.ErrCannotConvert) || !strings.Contains(err.Error(), `"{\"truncated\": \"this payload is l..."`) {
+		t.Errorf("expected conversion error quoting a prefix, got %v", err)
+	}
+	if _, err := jsonflex.AsEmbeddedJSON(jsonflex.AsAny())(jsonflex.Number(1)); !errors.Is(err, jsonflex.ErrCannotConvert) {
+		t.Errorf("expected conversion error for non-string, got %v", err)
+	}
+}
+
+func TestGetFieldBytes(t *testing.T) {
+	obj := jsonflex.Object{
+		"credits": jsonflex.Object{"director": "Nolan", "cast": jsonflex.Array{"DiCaprio"}},
+		"title":   "Inception",
+	}
+	got, err := jsonflex.GetFieldBytes(obj, "credits")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if diff := cmp.Diff(`{"cast":["DiCaprio"],"director":"Nolan"}`, string(got)); diff != "" {
+		t.Errorf("mismatch (-want +got):\n%s", diff)
+	}
+
+	if _, err := jsonflex.GetFieldBytes(obj, "missing"); !errors.Is(err, jsonflex.ErrFieldNotFound) {
+		t.Errorf("expected field not found error, got %v", err)
+	}
+	if _, err := jsonflex.GetFieldBytes(nil, "credits"); !errors.Is(err, jsonflex.ErrNilObject) {
+		t.Errorf("expected nil object error, got %v", err)
+	}
+}
+
+func TestAsObjectFromRaw(t *testing.T) {
+	var envelope struct {
+		Payload json.RawMessage `json:"payload"`
+		IDs     json.RawMessage `json:"ids"`
+	}
+	if err := json.Unmarshal([]byte(`{"payload": {"id": 28, "name": "Action"}, "ids": [1, 2]}`), &envelope); err != nil {
+		t.Fatal(err)
+	}
+
+	genre, err := jsonflex.AsObjectFromRaw[Genre]()(envelope.Payload)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if assertNoError(genre.ID())(t) != 28 {
+		t.Errorf("unexpected decoded genre: %v", genre)
+	}
+	if _, err := jsonflex.AsObjectFromRaw[Genre]()(jsonflex.Object{"id": jsonflex.Number(1)}); err != nil {
+		t.Errorf("expected decoded Object to be accepted, got %v", err)
+	}
+
+	ids, err := jsonflex.AsArrayFromRaw(jsonflex.AsInt32())([]byte(envelope.IDs))
+	if err != nil || len(ids) != 2 || ids[0] != 1 {
+		t.Errorf("expected [1 2], got %v with error %v", ids, err)
+	}
+
+	if _, err := jsonflex.AsObjectFromRaw[Genre]()(json.RawMessage(`{`)); !errors.Is(err, jsonflex.ErrCannotConvert) {
+		t.Errorf("expected conversion error for invalid JSON, got %v", err)
+	}
+	if _, err := jsonflex.AsObjectFromRaw[Genre]()(envelope.IDs); !errors.Is(err, jsonflex.ErrCannotConvert) {
+		t.Errorf("expected conversion error for raw array, got %v", err)
+	}
+	if _, err := jsonflex.AsObject[Genre]()(envelope.Payload); !errors.Is(err, jsonflex.ErrCannotConvert) {
+		t.Error("expected plain AsObject to keep rejecting raw JSON")
+	}
+}
+
+func TestParse(t *testing.T) {
+	data := []byte(`{"id": 9007199254740993, "ratio": 0.5, "count": 3, "big": 1e3, "tags": [1, "x"]}`)
+	cases := []struct {
+		name     string
+		mode     jsonflex.NumberMode
+		expected any
+		id       int64
+	}{
+		{
+			name: "float64",
+			mode: jsonflex.NumberModeFloat64,
+			id:   9007199254740992,
+			expected: jsonflex.Object{
+				"id": float64(9007199254740992), "ratio": 0.5, "count": float64(3), "big": float64(1000),
+				"tags": jsonflex.Array{float64(1), "x"},
+			},
+		},
+		{
+			name: "json number",
+			mode: jsonflex.NumberModeJSON,
+			id:   9007199254740993,
+			expected: jsonflex.Object{
+				"id": json.Number("9007199254740993"), "ratio": json.Number("0.5"), "count": json.Number("3"), "big": json.Number("1e3"),
+				"tags": jsonflex.Array{json.Number("1"), "x"},
+			},
+		},
+		{
+			name: "int or float",
+			mode: jsonflex.NumberModeIntOrFloat,
+			id:   9007199254740993,
+			expected: jsonflex.Object{
+				"id": int64(9007199254740993), "ratio": 0.5, "count": int64(3), "big": int64(1000),
+				"tags": jsonflex.Array{int64(1), "x"},
+			},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got, err := jsonflex.Parse(data, jsonflex.WithNumberMode(c.mode))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(c.expected, got); diff != "" {
+				t.Errorf("mismatch (-want +got):\n%s", diff)
+			}
+			obj, err := jsonflex.AsObject[jsonflex.Object]()(got)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if count, err := jsonflex.GetField(obj, "count", jsonflex.AsInt32()); err != nil || count != 3 {
+				t.Errorf("expected count 3, got %d with error %v", count, err)
+			}
+			if ratio, err := jsonflex.GetField(obj, "ratio", jsonflex.AsFloat64()); err != nil || ratio != 0.5 {
+				t.Errorf("expected ratio 0.5, got %v with error %v", ratio, err)
+			}
+
+			// Values above 2^53 are exact in the modes that preserve them.
+			if c.mode != jsonflex.NumberModeFloat64 {
+				if id, err := jsonflex.GetField(obj, "id", jsonflex.AsInt64FromString()); err != nil || id != c.id {
+					t.Errorf("expected id %d, got %d with error %v", c.id, id, err)
+				}
+				if id, err := jsonflex.GetField(obj, "id", jsonflex.AsUint64FromString()); err != nil || id != uint64(c.id) {
+					t.Errorf("expected unsigned id %d, got %d with error %v", c.id, id, err)
+				}
+			}
+			if id, err := jsonflex.GetField(obj, "id", jsonflex.AsStringCoerce()); err != nil || id != strconv.FormatInt(c.id, 10) {
+				t.Errorf("expected id text %d, got %q with error %v", c.id, id, err)
+			}
+			var decoded struct {
+				ID    int64  `json:"id"`
+				UID   uint64 `json:"uid"`
+				Count int8   `json:"count"`
+			}
+			obj["uid"] = obj["id"]
+			if err := jsonflex.DecodeStruct(obj, &decoded); err != nil || decoded.ID != c.id || decoded.UID != uint64(c.id) || decoded.Count != 3 {
+				t.Errorf("expected decoded id %d, got %+v with error %v", c.id, decoded, err)
+			}
+		})
+	}
+
+	// Integral literals that cannot become an exact int64 stay float64.
+	v, err := jsonflex.Parse([]byte(`[-9223372036854775809, 9007199254740993.0, 1e3]`), jsonflex.WithNumberMode(jsonflex.NumberModeIntOrFloat))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if diff := cmp.Diff(jsonflex.Array{float64(math.MinInt64), float64(9007199254740992), int64(1000)}, v); diff != "" {
+		t.Errorf("mismatch (-want +got):\n%s", diff)
+	}
+	for i, item := range v.(jsonflex.Array)[:2] {
+		if n, err := jsonflex.AsInt64FromString()(item); err == nil {
+			t.Errorf("item %d: expected error for inexact value, got %d", i, n)
+		}
+	}
+
+	if _, err := jsonflex.Parse([]byte(`{"a": 1} {"b": 2}`)); err == nil {
+		t.Error("expected error for trailing data")
+	}
+	if _, err := jsonflex.Parse([]byte(`{`)); err == nil {
+		t.Error("expected error for invalid JSON")
+	}
+	if v, err := jsonflex.Parse([]byte(" 1 \n")); err != nil || v != float64(1) {
+		t.Errorf("expected 1, got %v with error %v", v, err)
+	}
+
+	v, err = jsonflex.Parse([]byte(`[1.50, 2]`), jsonflex.WithNumberMode(jsonflex.NumberModeJSON))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	numbers, err := jsonflex.AsArray(func(v any) (json.Number, error) { return v.(json.Number), nil })(v)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := jsonflex.StringCompact(numbers); got != "[1.50, 2]" {
+		t.Errorf("expected json.Number to render as its text, got %s", got)
+	}
+}
@@ -0,0 +1,51 @@
+// Package jsonflextest provides helpers for testing code built on jsonflex,
+// such as accessor methods on types based on jsonflex.Object.
+package jsonflextest
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/krelinga/go-jsonflex"
+)
+
+// MustConvert returns v, failing the test immediately if err is non-nil.
+//
+//	v, err := movie.ID()
+//	id := jsonflextest.MustConvert(t, v, err)
+//
+// Use Must to wrap an accessor call directly.
+func MustConvert[T any](t testing.TB, v T, err error) T {
+	t.Helper()
+	if err != nil {
+		t.Fatalf("unexpected conversion error: %v", err)
+	}
+	return v
+}
+
+// Must is a curried form of MustConvert that accepts an accessor's return
+// values directly, since Go does not allow mixing a multi-value call with
+// other arguments:
+//
+//	id := jsonflextest.Must(movie.ID())(t)
+func Must[T any](v T, err error) func(testing.TB) T {
+	return func(t testing.TB) T {
+		t.Helper()
+		return MustConvert(t, v, err)
+	}
+}
+
+// AssertField extracts key from obj using conv and reports a test error if
+// the extraction fails or the result differs from want. Failure messages name
+// the field and include a diff of the values.
+func AssertField[T any](t testing.TB, obj jsonflex.Object, key string, conv jsonflex.Converter[T], want T) {
+	t.Helper()
+	got, err := jsonflex.GetField(obj, key, conv)
+	if err != nil {
+		t.Errorf("field %q: unexpected error: %v", key, err)
+		return
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("field %q: mismatch (-want +got):\n%s", key, diff)
+	}
+}
@@ -0,0 +1,69 @@
+package jsonflextest_test
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/krelinga/go-jsonflex"
+	"github.com/krelinga/go-jsonflex/jsonflextest"
+)
+
+// recorder captures failures instead of reporting them.
+type recorder struct {
+	testing.TB
+	errors []string
+	fatal  bool
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...any) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func (r *recorder) Fatalf(format string, args ...any) {
+	r.Errorf(format, args...)
+	r.fatal = true
+}
+
+func TestMustConvert(t *testing.T) {
+	v, err := jsonflex.AsInt32()(jsonflex.Number(7))
+	if got := jsonflextest.MustConvert(t, v, err); got != 7 {
+		t.Errorf("expected 7, got %d", got)
+	}
+	if got := jsonflextest.Must(jsonflex.AsString()("x"))(t); got != "x" {
+		t.Errorf("expected x, got %q", got)
+	}
+
+	r := &recorder{}
+	jsonflextest.MustConvert(r, 0, errors.New("boom"))
+	if !r.fatal || len(r.errors) != 1 || !strings.Contains(r.errors[0], "boom") {
+		t.Errorf("expected a fatal failure mentioning the error, got %v", r.errors)
+	}
+
+	r = &recorder{}
+	jsonflextest.Must(jsonflex.AsString()(nil))(r)
+	if !r.fatal {
+		t.Error("expected Must to fail fatally on error")
+	}
+}
+
+func TestAssertField(t *testing.T) {
+	obj := jsonflex.Object{"title": "Inception", "genre_ids": jsonflex.Array{jsonflex.Number(28)}}
+	jsonflextest.AssertField(t, obj, "title", jsonflex.AsString(), "Inception")
+	jsonflextest.AssertField(t, obj, "genre_ids", jsonflex.AsArray(jsonflex.AsInt32()), []int32{28})
+
+	r := &recorder{}
+	jsonflextest.AssertField(r, obj, "title", jsonflex.AsString(), "Interstellar")
+	if len(r.errors) != 1 || !strings.Contains(r.errors[0], `field "title"`) || !strings.Contains(r.errors[0], "Interstellar") {
+		t.Errorf("expected a mismatch naming the field, got %v", r.errors)
+	}
+
+	r = &recorder{}
+	jsonflextest.AssertField(r, obj, "missing", jsonflex.AsString(), "")
+	if len(r.errors) != 1 || !strings.Contains(r.errors[0], `field "missing"`) || r.fatal {
+		t.Errorf("expected a non-fatal error naming the field, got %v", r.errors)
+	}
+}
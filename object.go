@@ -0,0 +1,281 @@
+package jsonflex
+
+import (
+	"fmt"
+	"iter"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// Entry is a single key/value pair from a JSON object.
+type Entry[V any] struct {
+	Key   string
+	Value V
+}
+
+// Entries returns a Converter that converts an Object into a slice of
+// key/value entries sorted by key, converting each value with valueConv.
+// This gives a deterministic iteration order that ranging over a map cannot.
+// Conversion errors name the offending key.
+func Entries[V any](valueConv Converter[V]) Converter[[]Entry[V]] {
+	return func(v any) ([]Entry[V], error) {
+		obj, err := AsObject[Object]()(v)
+		if err != nil {
+			return nil, err
+		}
+		result := make([]Entry[V], 0, len(obj))
+		for key, raw := range SortedEntries(obj) {
+			value, err := valueConv(raw)
+			if err != nil {
+				return nil, fmt.Errorf("key %q: %w", key, err)
+			}
+			result = append(result, Entry[V]{Key: key, Value: value})
+		}
+		return result, nil
+	}
+}
+
+// AsObjectRequiring returns a Converter that converts a value like AsObject
+// and then checks that every key in required is present, so missing fields
+// are reported at the decode boundary rather than by a later accessor.
+// A key whose value is JSON null counts as present. If any keys are missing,
+// the error wraps ErrFieldNotFound and lists all of them.
+func AsObjectRequiring[T ~Object](required ...string) Converter[T] {
+	return func(v any) (T, error) {
+		obj, err := AsObject[T]()(v)
+		if err != nil {
+			return nil, err
+		}
+		var missing []string
+		for _, key := range required {
+			if _, ok := obj[key]; !ok {
+				missing = append(missing, key)
+			}
+		}
+		if len(missing) > 0 {
+			return nil, fmt.Errorf("%w: missing %s", ErrFieldNotFound, quoteKeys(missing))
+		}
+		return obj, nil
+	}
+}
+
+// AsObjectExactlyOneOf returns a Converter that converts a value like
+// AsObject and then checks that exactly one of keys is present with a
+// non-null value, as in unions expressed as sibling keys.
+// If none is, the error wraps ErrFieldNotFound. If several are, the error
+// wraps ErrCannotConvert and lists the keys that were present.
+func AsObjectExactlyOneOf[T ~Object](keys ...string) Converter[T] {
+	return func(v any) (T, error) {
+		obj, err := AsObject[T]()(v)
+		if err != nil {
+			return nil, err
+		}
+		var present []string
+		for _, key := range keys {
+			if value, exists := obj[key]; exists && value != nil {
+				present = append(present, key)
+			}
+		}
+		switch len(present) {
+		case 1:
+			return obj, nil
+		case 0:
+			return nil, fmt.Errorf("%w: expected exactly one of %s", ErrFieldNotFound, quoteKeys(keys))
+		default:
+			ce := newConversionError(v, "Object")
+			ce.Reason = fmt.Sprintf("expected exactly one of %s, got %s", quoteKeys(keys), quoteKeys(present))
+			return nil, ce
+		}
+	}
+}
+
+// AsOptionalObject returns a Converter that converts a value like AsObject,
+// but distinguishes JSON null from an empty object, as PATCH semantics need:
+// null yields a nil pointer, {} a pointer to an empty, non-nil T, and any
+// other object a pointer to it. With GetField, an absent field is still
+// reported as ErrFieldNotFound.
+func AsOptionalObject[T ~Object]() Converter[*T] {
+	return func(v any) (*T, error) {
+		if v == nil {
+			return nil, nil
+		}
+		obj, err := AsObject[T]()(v)
+		if err != nil {
+			return nil, err
+		}
+		if obj == nil {
+			obj = T{}
+		}
+		return &obj, nil
+	}
+}
+
+// SortedEntries returns an iterator over the key/value pairs of obj in sorted
+// key order, for use as `for k, v := range SortedEntries(obj)`.
+// A nil obj yields nothing.
+func SortedEntries(obj Object) iter.Seq2[string, any] {
+	return func(yield func(string, any) bool) {
+		for _, key := range slices.Sorted(maps.Keys(obj)) {
+			if !yield(key, obj[key]) {
+				return
+			}
+		}
+	}
+}
+
+// WithDefaults returns a Converter that, when given an Object, runs conv on a
+// merged view in which keys missing from the input take their values from
+// defaults. Neither the input nor defaults is modified.
+// Only top-level keys are merged; use WithDeepDefaults to also merge nested
+// objects. Types based on Object, such as `type Movie Object`, are merged too
+// and keep their type. Inputs that are not objects are passed to conv
+// unchanged.
+func WithDefaults[T any](defaults Object, conv Converter[T]) Converter[T] {
+	return func(v any) (T, error) {
+		v, _ = mapObject(v, func(obj Object) (Object, error) {
+			return mergeDefaults(obj, defaults, false), nil
+		})
+		return conv(v)
+	}
+}
+
+// WithDeepDefaults is like WithDefaults, but recursively merges nested objects
+// that are present in both the input and defaults.
+func WithDeepDefaults[T any](defaults Object, conv Converter[T]) Converter[T] {
+	return func(v any) (T, error) {
+		v, _ = mapObject(v, func(obj Object) (Object, error) {
+			return mergeDefaults(obj, defaults, true), nil
+		})
+		return conv(v)
+	}
+}
+
+func mergeDefaults(obj, defaults Object, deep bool) Object {
+	merged := make(Object, len(obj)+len(defaults))
+	for key, value := range defaults {
+		merged[key] = value
+	}
+	for key, value := range obj {
+		if nestedDefaults, ok := asPlainObject(defaults[key]); deep && ok {
+			value, _ = mapObject(value, func(nested Object) (Object, error) {
+				return mergeDefaults(nested, nestedDefaults, true), nil
+			})
+		}
+		merged[key] = value
+	}
+	return merged
+}
+
+// mapObject applies fn to v if it is an Object or a type based on Object, and
+// returns the result as v's type. Other values are returned unchanged.
+func mapObject(v any, fn func(Object) (Object, error)) (any, error) {
+	obj, ok := asPlainObject(v)
+	if !ok {
+		return v, nil
+	}
+	result, err := fn(obj)
+	if err != nil {
+		return nil, err
+	}
+	if _, plain := v.(Object); plain {
+		return result, nil
+	}
+	return reflect.ValueOf(result).Convert(reflect.TypeOf(v)).Interface(), nil
+}
+
+// WithKeyTransform returns a Converter that, when given an Object, runs conv
+// on a copy whose keys have been rewritten by fn. Nested objects are not
+// affected. If two keys map to the same transformed key the conversion fails
+// with ErrDuplicateKey, since silently picking one would depend on map
+// iteration order. Types based on Object are transformed too and keep their
+// type. Inputs that are not objects are passed to conv unchanged.
+func WithKeyTransform[T any](fn func(string) string, conv Converter[T]) Converter[T] {
+	return func(v any) (T, error) {
+		transformed, err := mapObject(v, func(obj Object) (Object, error) {
+			return transformKeys(obj, fn)
+		})
+		if err != nil {
+			var zero T
+			return zero, err
+		}
+		return conv(transformed)
+	}
+}
+
+// CaseFoldObject returns a Converter that converts a value like AsObject,
+// but with every key folded to lower case by strings.ToLower. This lets
+// accessors that look up lowercase keys, such as GetField(obj, "title", ...),
+// read from upstreams that send "Title" or "TITLE".
+// The contract is that lookups on the result must use lowercase keys; the
+// lookups themselves remain case-sensitive. Only the top-level keys are
+// folded. Keys that collide after folding fail with ErrDuplicateKey.
+func CaseFoldObject[T ~Object]() Converter[T] {
+	return func(v any) (T, error) {
+		obj, err := AsObject[Object]()(v)
+		if err != nil {
+			return nil, err
+		}
+		folded, err := transformKeys(obj, strings.ToLower)
+		if err != nil {
+			return nil, err
+		}
+		return T(folded), nil
+	}
+}
+
+func transformKeys(obj Object, fn func(string) string) (Object, error) {
+	result := make(Object, len(obj))
+	sources := make(map[string]string, len(obj))
+	for key, value := range obj {
+		newKey := fn(key)
+		if source, exists := sources[newKey]; exists {
+			first, second := min(source, key), max(source, key)
+			return nil, fmt.Errorf("%w %q: produced by both %q and %q", ErrDuplicateKey, newKey, first, second)
+		}
+		sources[newKey] = key
+		result[newKey] = value
+	}
+	return result, nil
+}
+
+// SnakeToCamel converts a snake_case key to camelCase, e.g. "genre_ids" to
+// "genreIds".
+func SnakeToCamel(s string) string {
+	sb := strings.Builder{}
+	upper := false
+	for _, r := range s {
+		if r == '_' {
+			upper = sb.Len() > 0
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		sb.WriteRune(r)
+	}
+	return sb.String()
+}
+
+// CamelToSnake converts a camelCase or PascalCase key to snake_case, e.g.
+// "genreIds" to "genre_ids". Runs of capitals are treated as one word, so
+// "posterURL" becomes "poster_url".
+func CamelToSnake(s string) string {
+	runes := []rune(s)
+	sb := strings.Builder{}
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			prevLower := i > 0 && (unicode.IsLower(runes[i-1]) || unicode.IsDigit(runes[i-1]))
+			acronymEnd := i > 0 && unicode.IsUpper(runes[i-1]) && i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if prevLower || acronymEnd {
+				sb.WriteRune('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		sb.WriteRune(r)
+	}
+	return sb.String()
+}
@@ -0,0 +1,252 @@
+package jsonflex_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/krelinga/go-jsonflex"
+)
+
+func TestEntries(t *testing.T) {
+	obj := jsonflex.Object{"b": jsonflex.Number(2), "a": jsonflex.Number(1), "c": jsonflex.Number(3)}
+	got, err := jsonflex.Entries(jsonflex.AsInt32())(obj)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := []jsonflex.Entry[int32]{{Key: "a", Value: 1}, {Key: "b", Value: 2}, {Key: "c", Value: 3}}
+	if diff := cmp.Diff(expected, got); diff != "" {
+		t.Errorf("mismatch (-want +got):\n%s", diff)
+	}
+
+	_, err = jsonflex.Entries(jsonflex.AsInt32())(jsonflex.Object{"a": jsonflex.Number(1), "b": "two"})
+	if !errors.Is(err, jsonflex.ErrCannotConvert) {
+		t.Errorf("expected conversion error, got %v", err)
+	}
+	if _, err := jsonflex.Entries(jsonflex.AsAny())(nil); !errors.Is(err, jsonflex.ErrNullValue) {
+		t.Errorf("expected null value error, got %v", err)
+	}
+}
+
+func TestAsObjectRequiring(t *testing.T) {
+	conv := jsonflex.AsObjectRequiring[Movie]("id", "title", "alt_title")
+	movie, err := conv(jsonflex.Object{"id": jsonflex.Number(1), "title": "Inception", "alt_title": nil})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if title, err := movie.Title(); err != nil || title != "Inception" {
+		t.Errorf("expected title Inception, got %q with error %v", title, err)
+	}
+
+	_, err = conv(jsonflex.Object{"title": "Inception"})
+	if !errors.Is(err, jsonflex.ErrFieldNotFound) {
+		t.Errorf("expected field not found error, got %v", err)
+	}
+	if want := `field not found: missing "id", "alt_title"`; err == nil || err.Error() != want {
+		t.Errorf("expected error %q, got %v", want, err)
+	}
+	if _, err := conv("nope"); !errors.Is(err, jsonflex.ErrCannotConvert) {
+		t.Errorf("expected conversion error, got %v", err)
+	}
+}
+
+func TestAsObjectExactlyOneOf(t *testing.T) {
+	conv := jsonflex.AsObjectExactlyOneOf[jsonflex.Object]("email", "phone", "address")
+	if _, err := conv(jsonflex.Object{"email": "a@example.com", "phone": nil}); err != nil {
+		t.Errorf("unexpected error with one present key: %v", err)
+	}
+
+	_, err := conv(jsonflex.Object{"name": "x", "phone": nil})
+	if !errors.Is(err, jsonflex.ErrFieldNotFound) {
+		t.Errorf("expected field not found error with no present keys, got %v", err)
+	}
+
+	_, err = conv(jsonflex.Object{"email": "a@example.com", "address": "1 Main St"})
+	if !errors.Is(err, jsonflex.ErrCannotConvert) {
+		t.Errorf("expected conversion error with two present keys, got %v", err)
+	}
+	if want := `got "email", "address"`; err == nil || !strings.Contains(err.Error(), want) {
+		t.Errorf("expected error to mention %s, got %v", want, err)
+	}
+}
+
+func TestAsOptionalObject(t *testing.T) {
+	conv := jsonflex.AsOptionalObject[Genre]()
+	cases := []struct {
+		name     string
+		input    any
+		expected *Genre
+	}{
+		{name: "null", input: nil, expected: nil},
+		{name: "empty", input: jsonflex.Object{}, expected: &Genre{}},
+		{name: "populated", input: jsonflex.Object{"id": jsonflex.Number(28)}, expected: &Genre{"id": jsonflex.Number(28)}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got, err := conv(c.input)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(c.expected, got); diff != "" {
+				t.Errorf("mismatch (-want +got):\n%s", diff)
+			}
+			if got != nil && *got == nil {
+				t.Error("expected a non-nil object")
+			}
+		})
+	}
+
+	if _, err := conv("x"); !errors.Is(err, jsonflex.ErrCannotConvert) {
+		t.Errorf("expected conversion error, got %v", err)
+	}
+	if _, err := jsonflex.GetField(jsonflex.Object{}, "meta", conv); !errors.Is(err, jsonflex.ErrFieldNotFound) {
+		t.Errorf("expected field not found error, got %v", err)
+	}
+}
+
+func TestSortedEntries(t *testing.T) {
+	obj := jsonflex.Object{"b": jsonflex.Number(2), "a": "one", "c": nil}
+	var keys []string
+	var values []any
+	for k, v := range jsonflex.SortedEntries(obj) {
+		keys = append(keys, k)
+		values = append(values, v)
+	}
+	if diff := cmp.Diff([]string{"a", "b", "c"}, keys); diff != "" {
+		t.Errorf("mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]any{"one", jsonflex.Number(2), nil}, values); diff != "" {
+		t.Errorf("mismatch (-want +got):\n%s", diff)
+	}
+
+	for k := range jsonflex.SortedEntries(obj) {
+		if k != "a" {
+			t.Errorf("expected first key a, got %q", k)
+		}
+		break
+	}
+	for k := range jsonflex.SortedEntries(nil) {
+		t.Errorf("expected nothing from nil object, got key %q", k)
+	}
+}
+
+func TestWithDefaults(t *testing.T) {
+	defaults := jsonflex.Object{
+		"adult": false,
+		"title": "untitled",
+		"meta":  jsonflex.Object{"lang": "en", "region": "US"},
+	}
+	input := jsonflex.Object{
+		"title": "Inception",
+		"meta":  jsonflex.Object{"lang": "fr"},
+	}
+
+	shallow, err := jsonflex.WithDefaults(defaults, jsonflex.AsObject[jsonflex.Object]())(input)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := jsonflex.Object{
+		"adult": false,
+		"title": "Inception",
+		"meta":  jsonflex.Object{"lang": "fr"},
+	}
+	if diff := cmp.Diff(expected, shallow); diff != "" {
+		t.Errorf("mismatch (-want +got):\n%s", diff)
+	}
+
+	deep, err := jsonflex.WithDeepDefaults(defaults, jsonflex.AsObject[jsonflex.Object]())(input)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected["meta"] = jsonflex.Object{"lang": "fr", "region": "US"}
+	if diff := cmp.Diff(expected, deep); diff != "" {
+		t.Errorf("mismatch (-want +got):\n%s", diff)
+	}
+
+	if _, exists := input["adult"]; exists {
+		t.Error("expected input to be left unmodified")
+	}
+	if _, err := jsonflex.WithDefaults(defaults, jsonflex.AsObject[Movie]())(nil); !errors.Is(err, jsonflex.ErrNullValue) {
+		t.Errorf("expected null value error for non-object input, got %v", err)
+	}
+
+	// Typed objects, including nested ones, are merged and keep their type.
+	shallowTyped, err := jsonflex.WithDefaults(jsonflex.Object{"a": jsonflex.Number(1)}, jsonflex.AsAny())(Movie{"b": jsonflex.Number(2)})
+	if diff := cmp.Diff(any(Movie{"a": jsonflex.Number(1), "b": jsonflex.Number(2)}), shallowTyped); err != nil || diff != "" {
+		t.Errorf("mismatch with error %v (-want +got):\n%s", err, diff)
+	}
+	typed, err := jsonflex.WithDeepDefaults(defaults, jsonflex.AsAny())(Movie{"meta": Genre{"lang": "fr"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expectedTyped := Movie{"adult": false, "title": "untitled", "meta": Genre{"lang": "fr", "region": "US"}}
+	if diff := cmp.Diff(any(expectedTyped), typed); diff != "" {
+		t.Errorf("mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestWithKeyTransform(t *testing.T) {
+	camel := jsonflex.Object{"genreIds": jsonflex.Array{jsonflex.Number(28)}, "title": "Inception"}
+	movie, err := jsonflex.WithKeyTransform(jsonflex.CamelToSnake, jsonflex.AsObject[Movie]())(camel)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ids := assertNoError(movie.GenreIDs())(t); len(ids) != 1 || ids[0] != 28 {
+		t.Errorf("expected genre ids [28], got %v", ids)
+	}
+
+	typed, err := jsonflex.WithKeyTransform(jsonflex.CamelToSnake, jsonflex.AsAny())(Movie{"genreIds": jsonflex.Array{}})
+	if diff := cmp.Diff(any(Movie{"genre_ids": jsonflex.Array{}}), typed); err != nil || diff != "" {
+		t.Errorf("mismatch for typed input with error %v (-want +got):\n%s", err, diff)
+	}
+
+	colliding := jsonflex.Object{"genreIds": jsonflex.Array{}, "genre_ids": jsonflex.Array{}}
+	_, err = jsonflex.WithKeyTransform(jsonflex.CamelToSnake, jsonflex.AsObject[Movie]())(colliding)
+	if !errors.Is(err, jsonflex.ErrDuplicateKey) {
+		t.Errorf("expected duplicate key error, got %v", err)
+	}
+}
+
+func TestCaseFoldObject(t *testing.T) {
+	movie, err := jsonflex.CaseFoldObject[Movie]()(jsonflex.Object{"Title": "Inception", "ID": jsonflex.Number(1)})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if title := assertNoError(movie.Title())(t); title != "Inception" {
+		t.Errorf("expected title Inception, got %q", title)
+	}
+	if id := assertNoError(movie.ID())(t); id != 1 {
+		t.Errorf("expected id 1, got %d", id)
+	}
+
+	_, err = jsonflex.CaseFoldObject[Movie]()(jsonflex.Object{"Title": "a", "title": "b"})
+	if !errors.Is(err, jsonflex.ErrDuplicateKey) {
+		t.Errorf("expected duplicate key error, got %v", err)
+	}
+	if _, err := jsonflex.CaseFoldObject[Movie]()(nil); !errors.Is(err, jsonflex.ErrNullValue) {
+		t.Errorf("expected null value error, got %v", err)
+	}
+}
+
+func TestKeyCaseHelpers(t *testing.T) {
+	cases := []struct {
+		snake string
+		camel string
+	}{
+		{snake: "title", camel: "title"},
+		{snake: "genre_ids", camel: "genreIds"},
+		{snake: "release_date_utc", camel: "releaseDateUtc"},
+	}
+	for _, c := range cases {
+		if got := jsonflex.SnakeToCamel(c.snake); got != c.camel {
+			t.Errorf("SnakeToCamel(%q) = %q, want %q", c.snake, got, c.camel)
+		}
+		if got := jsonflex.CamelToSnake(c.camel); got != c.snake {
+			t.Errorf("CamelToSnake(%q) = %q, want %q", c.camel, got, c.snake)
+		}
+	}
+	if got := jsonflex.CamelToSnake("PosterURLPath2x"); got != "poster_url_path2x" {
+		t.Errorf("CamelToSnake(%q) = %q, want %q", "PosterURLPath2x", got, "poster_url_path2x")
+	}
+}
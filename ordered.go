@@ -0,0 +1,84 @@
+package jsonflex
+
+import (
+	"fmt"
+	"iter"
+	"slices"
+)
+
+// OrderedMap is a map that remembers the order in which keys were first set.
+// The zero value is an empty map ready to use.
+type OrderedMap[K comparable, V any] struct {
+	keys   []K
+	values map[K]V
+}
+
+// Set stores value under key. New keys are appended to the iteration order;
+// existing keys keep their original position.
+func (m *OrderedMap[K, V]) Set(key K, value V) {
+	if m.values == nil {
+		m.values = make(map[K]V)
+	}
+	if _, exists := m.values[key]; !exists {
+		m.keys = append(m.keys, key)
+	}
+	m.values[key] = value
+}
+
+// Get returns the value stored under key and whether it was present.
+func (m *OrderedMap[K, V]) Get(key K) (V, bool) {
+	value, ok := m.values[key]
+	return value, ok
+}
+
+// Len returns the number of keys in the map.
+func (m *OrderedMap[K, V]) Len() int {
+	return len(m.keys)
+}
+
+// Keys returns the keys in insertion order.
+func (m *OrderedMap[K, V]) Keys() []K {
+	return slices.Clone(m.keys)
+}
+
+// All returns an iterator over the key/value pairs in insertion order, for
+// use as for k, v := range m.All().
+func (m *OrderedMap[K, V]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for _, key := range m.keys {
+			if !yield(key, m.values[key]) {
+				return
+			}
+		}
+	}
+}
+
+// AsOrderedMap returns a Converter for ordered maps encoded as arrays of
+// single-entry objects, such as [{"first": ...}, {"second": ...}].
+// Since JSON objects carry no reliable key order, this entries idiom is the
+// only input accepted; the resulting map iterates in array order. Elements
+// must have exactly one key, and duplicate keys fail with ErrDuplicateKey.
+func AsOrderedMap[V any](valueConv Converter[V]) Converter[*OrderedMap[string, V]] {
+	return func(v any) (*OrderedMap[string, V], error) {
+		objs, err := AsArray(AsObject[Object]())(v)
+		if err != nil {
+			return nil, err
+		}
+		result := &OrderedMap[string, V]{}
+		for i, obj := range objs {
+			key, value, err := singleEntry(obj)
+			if err != nil {
+				return nil, fmt.Errorf("item %d: %w", i, err)
+			}
+			if _, exists := result.Get(key); exists {
+				return nil, fmt.Errorf("item %d: %w %q", i, ErrDuplicateKey, key)
+			}
+			converted, err := valueConv(value)
+			if err != nil {
+				return nil, fmt.Errorf("item %d: key %q: %w", i, key, err)
+			}
+			result.Set(key, converted)
+		}
+		return result, nil
+	}
+}
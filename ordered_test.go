@@ -0,0 +1,45 @@
+package jsonflex_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/krelinga/go-jsonflex"
+)
+
+func TestAsOrderedMap(t *testing.T) {
+	steps := jsonflex.Array{
+		jsonflex.Object{"preheat": jsonflex.Number(5)},
+		jsonflex.Object{"mix": jsonflex.Number(10)},
+		jsonflex.Object{"bake": jsonflex.Number(30)},
+	}
+	got, err := jsonflex.AsOrderedMap(jsonflex.AsInt32())(steps)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if diff := cmp.Diff([]string{"preheat", "mix", "bake"}, got.Keys()); diff != "" {
+		t.Errorf("mismatch (-want +got):\n%s", diff)
+	}
+	if v, ok := got.Get("mix"); !ok || v != 10 {
+		t.Errorf("expected mix to be 10, got %d (present: %v)", v, ok)
+	}
+	if _, ok := got.Get("serve"); ok {
+		t.Error("expected serve to be absent")
+	}
+
+	var total int32
+	var order []string
+	for k, v := range got.All() {
+		order = append(order, k)
+		total += v
+	}
+	if total != 45 || got.Len() != 3 || len(order) != 3 || order[0] != "preheat" {
+		t.Errorf("unexpected iteration: order %v, total %d", order, total)
+	}
+
+	dupes := append(steps, jsonflex.Object{"mix": jsonflex.Number(1)})
+	if _, err := jsonflex.AsOrderedMap(jsonflex.AsInt32())(dupes); !errors.Is(err, jsonflex.ErrDuplicateKey) {
+		t.Errorf("expected duplicate key error, got %v", err)
+	}
+}
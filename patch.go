@@ -0,0 +1,61 @@
+package jsonflex
+
+import (
+	"fmt"
+	"slices"
+)
+
+// PatchOp is a single operation of a JSON Patch document (RFC 6902).
+type PatchOp struct {
+	// Op is one of "add", "remove", "replace", "move", "copy", or "test".
+	Op string
+	// Path is the JSON Pointer the operation targets.
+	Path string
+	// From is the source JSON Pointer of "move" and "copy"; otherwise empty.
+	From string
+	// Value is the operand of "add", "replace", and "test", which may be nil
+	// for JSON null; otherwise nil.
+	Value any
+}
+
+var patchOps = []string{"add", "remove", "replace", "move", "copy", "test"}
+
+// AsJSONPatch returns a Converter that converts a JSON Patch document, an
+// array of {"op", "path", ...} objects, into a []PatchOp.
+// Every element must have a standard op and a string path, plus a "value"
+// member for add, replace and test (a null value counts as present) or a
+// string "from" member for move and copy. Errors name the element index.
+// The pointers themselves are not validated.
+func AsJSONPatch() Converter[[]PatchOp] {
+	return AsArray(asPatchOp)
+}
+
+func asPatchOp(v any) (PatchOp, error) {
+	obj, err := AsObject[Object]()(v)
+	if err != nil {
+		return PatchOp{}, err
+	}
+	var op PatchOp
+	if op.Op, err = GetField(obj, "op", AsString()); err != nil {
+		return PatchOp{}, err
+	}
+	if !slices.Contains(patchOps, op.Op) {
+		ce := newConversionError(v, "PatchOp")
+		ce.Reason = fmt.Sprintf("unknown op %q", op.Op)
+		return PatchOp{}, ce
+	}
+	if op.Path, err = GetField(obj, "path", AsString()); err != nil {
+		return PatchOp{}, err
+	}
+	switch op.Op {
+	case "add", "replace", "test":
+		if op.Value, err = GetField(obj, "value", AsAny()); err != nil {
+			return PatchOp{}, fmt.Errorf("op %q: %w", op.Op, err)
+		}
+	case "move", "copy":
+		if op.From, err = GetField(obj, "from", AsString()); err != nil {
+			return PatchOp{}, fmt.Errorf("op %q: %w", op.Op, err)
+		}
+	}
+	return op, nil
+}
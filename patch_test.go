@@ -0,0 +1,61 @@
+package jsonflex_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/krelinga/go-jsonflex"
+)
+
+func TestAsJSONPatch(t *testing.T) {
+	doc := jsonflex.Array{
+		jsonflex.Object{"op": "add", "path": "/tags/-", "value": "new"},
+		jsonflex.Object{"op": "remove", "path": "/draft"},
+		jsonflex.Object{"op": "replace", "path": "/rating", "value": nil},
+		jsonflex.Object{"op": "move", "from": "/old", "path": "/new"},
+		jsonflex.Object{"op": "copy", "from": "/a", "path": "/b"},
+		jsonflex.Object{"op": "test", "path": "/id", "value": jsonflex.Number(1)},
+	}
+	got, err := jsonflex.AsJSONPatch()(doc)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := []jsonflex.PatchOp{
+		{Op: "add", Path: "/tags/-", Value: "new"},
+		{Op: "remove", Path: "/draft"},
+		{Op: "replace", Path: "/rating"},
+		{Op: "move", Path: "/new", From: "/old"},
+		{Op: "copy", Path: "/b", From: "/a"},
+		{Op: "test", Path: "/id", Value: jsonflex.Number(1)},
+	}
+	if diff := cmp.Diff(expected, got); diff != "" {
+		t.Errorf("mismatch (-want +got):\n%s", diff)
+	}
+
+	cases := []struct {
+		name     string
+		op       jsonflex.Object
+		sentinel error
+		message  string
+	}{
+		{name: "unknown op", op: jsonflex.Object{"op": "delete", "path": "/a"}, sentinel: jsonflex.ErrCannotConvert, message: `unknown op "delete"`},
+		{name: "missing op", op: jsonflex.Object{"path": "/a"}, sentinel: jsonflex.ErrFieldNotFound, message: `"op"`},
+		{name: "missing path", op: jsonflex.Object{"op": "remove"}, sentinel: jsonflex.ErrFieldNotFound, message: `"path"`},
+		{name: "missing value", op: jsonflex.Object{"op": "add", "path": "/a"}, sentinel: jsonflex.ErrFieldNotFound, message: `op "add"`},
+		{name: "missing from", op: jsonflex.Object{"op": "move", "path": "/a"}, sentinel: jsonflex.ErrFieldNotFound, message: `op "move"`},
+		{name: "non-string path", op: jsonflex.Object{"op": "remove", "path": jsonflex.Number(1)}, sentinel: jsonflex.ErrCannotConvert, message: `field "path"`},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			_, err := jsonflex.AsJSONPatch()(jsonflex.Array{doc[0], c.op})
+			if !errors.Is(err, c.sentinel) {
+				t.Errorf("expected %v, got %v", c.sentinel, err)
+			}
+			if err == nil || !strings.Contains(err.Error(), "item 1") || !strings.Contains(err.Error(), c.message) {
+				t.Errorf("expected error naming item 1 and mentioning %s, got %v", c.message, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,185 @@
+package jsonflex
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// GetAll walks path from root and collects every value found at its end,
+// converted with conv.
+//
+// Whenever an array is encountered before the end of the path, the walk fans
+// out across all of its elements, so GetAll(root, AsInt32(), "results",
+// "genres", "id") gathers every id under results[].genres[]. Missing keys and
+// null values along the way are skipped rather than reported, matching the
+// collection semantics of the lookup. Values that are neither objects nor
+// arrays where the path continues, and leaf values that fail conversion,
+// abort the walk with an error naming their location.
+func GetAll[T any](root any, conv Converter[T], path ...string) ([]T, error) {
+	var result []T
+	if err := getAll(root, conv, path, nil, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func getAll[T any](node any, conv Converter[T], path []string, loc []string, result *[]T) error {
+	if len(path) == 0 {
+		converted, err := conv(node)
+		if err != nil {
+			return fmt.Errorf("%s: %w", formatLocation(loc), err)
+		}
+		*result = append(*result, converted)
+		return nil
+	}
+	if node == nil {
+		return nil
+	}
+	if arr, ok := asPlainArray(node); ok {
+		for i, item := range arr {
+			if err := getAll(item, conv, path, append(loc, fmt.Sprintf("[%d]", i)), result); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if obj, ok := asPlainObject(node); ok {
+		value, exists := obj[path[0]]
+		if !exists {
+			return nil
+		}
+		return getAll(value, conv, path[1:], append(loc, "."+path[0]), result)
+	}
+	return fmt.Errorf("%s: %w", formatLocation(loc), newConversionError(node, "Object or Array"))
+}
+
+func formatLocation(loc []string) string {
+	if len(loc) == 0 {
+		return "root"
+	}
+	return strings.TrimPrefix(strings.Join(loc, ""), ".")
+}
+
+// Path is a reusable sequence of object keys and array indices, built with
+// NewPath, Key and Index and applied with Get or GetAt:
+//
+//	id := jsonflex.NewPath("results").Index(0).Key("id")
+//	v, err := jsonflex.GetAt(doc, id, jsonflex.AsInt32())
+//
+// Key and Index return a new Path and never modify their receiver, so a
+// common prefix can be shared between several paths. The zero Path refers to
+// the root value.
+type Path struct {
+	segments []pathSegment
+}
+
+type pathSegment struct {
+	key   string
+	index int
+	isKey bool
+}
+
+// NewPath returns a Path made of the given object keys.
+func NewPath(keys ...string) Path {
+	var p Path
+	for _, key := range keys {
+		p = p.Key(key)
+	}
+	return p
+}
+
+// Key returns p extended with an object key.
+func (p Path) Key(key string) Path {
+	return Path{segments: append(slices.Clip(p.segments), pathSegment{key: key, isKey: true})}
+}
+
+// Index returns p extended with an array index.
+func (p Path) Index(i int) Path {
+	return Path{segments: append(slices.Clip(p.segments), pathSegment{index: i})}
+}
+
+// String returns p as a JSON pointer (RFC 6901), such as "/results/0/id".
+// The root Path is the empty string.
+func (p Path) String() string {
+	var b strings.Builder
+	for _, seg := range p.segments {
+		b.WriteByte('/')
+		if seg.isKey {
+			b.WriteString(strings.NewReplacer("~", "~0", "/", "~1").Replace(seg.key))
+		} else {
+			b.WriteString(strconv.Itoa(seg.index))
+		}
+	}
+	return b.String()
+}
+
+// Get walks p from root and returns the raw value at its end.
+// Missing keys wrap ErrFieldNotFound, out-of-range indices wrap ErrNotFound,
+// and nulls or mismatched values along the way are reported with their
+// location, in the same form as GetAll.
+func (p Path) Get(root any) (any, error) {
+	node := root
+	var loc []string
+	for _, seg := range p.segments {
+		if node == nil {
+			return nil, fmt.Errorf("%s: %w", formatLocation(loc), ErrNullValue)
+		}
+		if seg.isKey {
+			obj, ok := asPlainObject(node)
+			if !ok {
+				return nil, fmt.Errorf("%s: %w", formatLocation(loc), newConversionError(node, "Object"))
+			}
+			value, exists := obj[seg.key]
+			if !exists {
+				return nil, fmt.Errorf("%s: %w %q", formatLocation(loc), ErrFieldNotFound, seg.key)
+			}
+			node = value
+			loc = append(loc, seg.location())
+			continue
+		}
+		arr, ok := asPlainArray(node)
+		if !ok {
+			return nil, fmt.Errorf("%s: %w", formatLocation(loc), newConversionError(node, "Array"))
+		}
+		if seg.index < 0 || seg.index >= len(arr) {
+			return nil, fmt.Errorf("%s: index %d out of range for length %d: %w", formatLocation(loc), seg.index, len(arr), ErrNotFound)
+		}
+		node = arr[seg.index]
+		loc = append(loc, seg.location())
+	}
+	return node, nil
+}
+
+// GetAt walks p from root and converts the value at its end with conv.
+// It is the typed form of p.Get; conversion errors are prefixed with the
+// value's location.
+func GetAt[T any](root any, p Path, conv Converter[T]) (T, error) {
+	var zero T
+	value, err := p.Get(root)
+	if err != nil {
+		return zero, err
+	}
+	result, err := conv(value)
+	if err != nil {
+		return zero, fmt.Errorf("%s: %w", p.location(), err)
+	}
+	return result, nil
+}
+
+// location formats p like the locations in GetAll errors.
+func (p Path) location() string {
+	loc := make([]string, 0, len(p.segments))
+	for _, seg := range p.segments {
+		loc = append(loc, seg.location())
+	}
+	return formatLocation(loc)
+}
+
+func (s pathSegment) location() string {
+	if s.isKey {
+		return "." + s.key
+	}
+	return fmt.Sprintf("[%d]", s.index)
+}
@@ -0,0 +1,118 @@
+package jsonflex_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/krelinga/go-jsonflex"
+)
+
+func TestGetAll(t *testing.T) {
+	root := jsonflex.Object{
+		"results": jsonflex.Array{
+			jsonflex.Object{"genres": jsonflex.Array{
+				jsonflex.Object{"id": jsonflex.Number(28)},
+				jsonflex.Object{"id": jsonflex.Number(12)},
+			}},
+			jsonflex.Object{"title": "no genres"},
+			jsonflex.Object{"genres": nil},
+			jsonflex.Object{"genres": jsonflex.Array{
+				jsonflex.Object{"name": "missing id"},
+				jsonflex.Object{"id": jsonflex.Number(878)},
+			}},
+		},
+	}
+	got, err := jsonflex.GetAll(root, jsonflex.AsInt32(), "results", "genres", "id")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if diff := cmp.Diff([]int32{28, 12, 878}, got); diff != "" {
+		t.Errorf("mismatch (-want +got):\n%s", diff)
+	}
+
+	_, err = jsonflex.GetAll(root, jsonflex.AsString(), "results", "genres", "id")
+	if !errors.Is(err, jsonflex.ErrCannotConvert) || !strings.Contains(err.Error(), "results[0].genres[0].id") {
+		t.Errorf("expected conversion error naming results[0].genres[0].id, got %v", err)
+	}
+
+	_, err = jsonflex.GetAll(root, jsonflex.AsString(), "results", "title", "length")
+	if !errors.Is(err, jsonflex.ErrCannotConvert) || !strings.Contains(err.Error(), "results[1].title") {
+		t.Errorf("expected conversion error naming results[1].title, got %v", err)
+	}
+
+	movie := Movie{"genres": jsonflex.Array{Genre{"id": jsonflex.Number(28)}, jsonflex.Object{"id": jsonflex.Number(12)}}}
+	got, err = jsonflex.GetAll(movie, jsonflex.AsInt32(), "genres", "id")
+	if err != nil {
+		t.Fatalf("unexpected error for typed root: %v", err)
+	}
+	if diff := cmp.Diff([]int32{28, 12}, got); diff != "" {
+		t.Errorf("mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestPath(t *testing.T) {
+	doc := jsonflex.Object{
+		"results": jsonflex.Array{
+			jsonflex.Object{"id": jsonflex.Number(1), "a/b~c": "escaped"},
+			jsonflex.Object{"id": jsonflex.Number(2), "name": nil},
+		},
+	}
+	results := jsonflex.NewPath("results")
+	first := results.Index(0)
+	second := results.Index(1)
+
+	for i, p := range []jsonflex.Path{first.Key("id"), second.Key("id")} {
+		got, err := jsonflex.GetAt(doc, p, jsonflex.AsInt32())
+		if err != nil || got != int32(i+1) {
+			t.Errorf("%s: expected %d, got %d with error %v", p, i+1, got, err)
+		}
+	}
+	if got, err := first.Key("a/b~c").Get(doc); err != nil || got != "escaped" {
+		t.Errorf("expected escaped, got %v with error %v", got, err)
+	}
+	movie := Movie{"results": jsonflex.Array{Movie{"id": jsonflex.Number(3)}}}
+	if got, err := jsonflex.GetAt(movie, first.Key("id"), jsonflex.AsInt32()); err != nil || got != 3 {
+		t.Errorf("expected 3 from a typed root, got %d with error %v", got, err)
+	}
+	if got, err := (jsonflex.Path{}).Get(doc); err != nil || got == nil {
+		t.Errorf("expected the root value, got %v with error %v", got, err)
+	}
+
+	pointers := []struct {
+		path     jsonflex.Path
+		expected string
+	}{
+		{path: jsonflex.Path{}, expected: ""},
+		{path: first.Key("id"), expected: "/results/0/id"},
+		{path: first.Key("a/b~c"), expected: "/results/0/a~1b~0c"},
+	}
+	for _, c := range pointers {
+		if got := c.path.String(); got != c.expected {
+			t.Errorf("expected %q, got %q", c.expected, got)
+		}
+	}
+
+	failures := []struct {
+		name     string
+		path     jsonflex.Path
+		sentinel error
+		location string
+	}{
+		{name: "missing key", path: first.Key("missing"), sentinel: jsonflex.ErrFieldNotFound, location: "results[0]: "},
+		{name: "out of range", path: results.Index(2).Key("id"), sentinel: jsonflex.ErrNotFound, location: "results: "},
+		{name: "index on object", path: jsonflex.NewPath().Index(0), sentinel: jsonflex.ErrCannotConvert, location: "root: "},
+		{name: "key on array", path: results.Key("id"), sentinel: jsonflex.ErrCannotConvert, location: "results: "},
+		{name: "through null", path: second.Key("name").Key("first"), sentinel: jsonflex.ErrNullValue, location: "results[1].name: "},
+		{name: "conversion", path: first.Key("a/b~c"), sentinel: jsonflex.ErrCannotConvert, location: "results[0].a/b~c: "},
+	}
+	for _, c := range failures {
+		t.Run(c.name, func(t *testing.T) {
+			_, err := jsonflex.GetAt(doc, c.path, jsonflex.AsInt32())
+			if !errors.Is(err, c.sentinel) || !strings.HasPrefix(err.Error(), c.location) {
+				t.Errorf("expected %v at %q, got %v", c.sentinel, c.location, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,79 @@
+package jsonflex
+
+// Result holds the outcome of a conversion: either a value or an error.
+// It offers a fluent alternative to threading (T, error) pairs through
+// call chains; the tuple-returning API remains the primary one.
+type Result[T any] struct {
+	value T
+	err   error
+}
+
+// Ok returns a successful Result holding v.
+func Ok[T any](v T) Result[T] {
+	return Result[T]{value: v}
+}
+
+// Err returns a failed Result holding err.
+func Err[T any](err error) Result[T] {
+	return Result[T]{err: err}
+}
+
+// ResultOf builds a Result from a (T, error) pair, such as the return values
+// of an accessor method.
+func ResultOf[T any](v T, err error) Result[T] {
+	if err != nil {
+		return Err[T](err)
+	}
+	return Ok(v)
+}
+
+// GetFieldResult is like GetField, but returns a Result.
+func GetFieldResult[T any](obj Object, key string, conv Converter[T]) Result[T] {
+	return ResultOf(GetField(obj, key, conv))
+}
+
+// Value returns the held value and error as a tuple.
+func (r Result[T]) Value() (T, error) {
+	return r.value, r.err
+}
+
+// Err returns the held error, or nil if the Result is successful.
+func (r Result[T]) Err() error {
+	return r.err
+}
+
+// Or returns the held value, or def if the Result holds an error.
+func (r Result[T]) Or(def T) T {
+	if r.err != nil {
+		return def
+	}
+	return r.value
+}
+
+// Must returns the held value, panicking if the Result holds an error.
+func (r Result[T]) Must() T {
+	if r.err != nil {
+		panic(r.err)
+	}
+	return r.value
+}
+
+// Map applies fn to the value of a successful Result.
+// Failed Results are passed through unchanged.
+// Map is a function rather than a method because Go methods cannot introduce
+// the new type parameter U.
+func Map[T, U any](r Result[T], fn func(T) U) Result[U] {
+	if r.err != nil {
+		return Err[U](r.err)
+	}
+	return Ok(fn(r.value))
+}
+
+// AndThen applies fn, which may itself fail, to the value of a successful
+// Result. Failed Results are passed through unchanged.
+func AndThen[T, U any](r Result[T], fn func(T) Result[U]) Result[U] {
+	if r.err != nil {
+		return Err[U](r.err)
+	}
+	return fn(r.value)
+}
@@ -0,0 +1,71 @@
+package jsonflex_test
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/krelinga/go-jsonflex"
+)
+
+func TestResult(t *testing.T) {
+	movie := jsonflex.Object{"title": "Inception", "genres": jsonflex.Array{
+		jsonflex.Object{"id": jsonflex.Number(28), "name": "Action"},
+	}}
+
+	title := jsonflex.GetFieldResult(movie, "title", jsonflex.AsString())
+	if got := title.Must(); got != "Inception" {
+		t.Errorf("expected 'Inception', got %q", got)
+	}
+	upper := jsonflex.Map(title, strings.ToUpper)
+	if got := upper.Or(""); got != "INCEPTION" {
+		t.Errorf("expected 'INCEPTION', got %q", got)
+	}
+
+	missing := jsonflex.GetFieldResult(movie, "tagline", jsonflex.AsString())
+	if got := missing.Or("n/a"); got != "n/a" {
+		t.Errorf("expected default 'n/a', got %q", got)
+	}
+	if _, err := jsonflex.Map(missing, strings.ToUpper).Value(); !errors.Is(err, jsonflex.ErrFieldNotFound) {
+		t.Errorf("expected field not found error to pass through Map, got %v", err)
+	}
+
+	firstGenre := jsonflex.AndThen(
+		jsonflex.GetFieldResult(movie, "genres", jsonflex.AsArray(jsonflex.AsObject[Genre]())),
+		func(genres []Genre) jsonflex.Result[string] {
+			if len(genres) == 0 {
+				return jsonflex.Err[string](errors.New("no genres"))
+			}
+			return jsonflex.ResultOf(genres[0].Name())
+		},
+	)
+	if got, err := firstGenre.Value(); err != nil || got != "Action" {
+		t.Errorf("expected 'Action', got %q with error %v", got, err)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("expected Must to panic on error")
+		}
+	}()
+	missing.Must()
+}
+
+func ExampleGetFieldResult() {
+	movie := jsonflex.Object{"title": "Inception"}
+
+	// Tuple style.
+	title, err := jsonflex.GetField(movie, "title", jsonflex.AsString())
+	if err != nil {
+		title = "untitled"
+	}
+	fmt.Println(title)
+
+	// Result style.
+	tagline := jsonflex.GetFieldResult(movie, "tagline", jsonflex.AsString()).Or("no tagline")
+	fmt.Println(tagline)
+	// Output:
+	// Inception
+	// no tagline
+}
@@ -0,0 +1,272 @@
+package jsonflex
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// AsFiniteFloat64 returns a Converter that converts a value to float64 like
+// AsFloat64, but additionally rejects NaN and ±Inf with ErrCannotConvert.
+// Use this where a non-finite value would silently poison downstream math.
+func AsFiniteFloat64() Converter[float64] {
+	return func(v any) (float64, error) {
+		f, err := AsFloat64()(v)
+		if err != nil {
+			return 0, err
+		}
+		if math.IsNaN(f) || math.IsInf(f, 0) {
+			err := newConversionError(v, "finite float64")
+			err.Reason = fmt.Sprintf("value is %v", f)
+			return 0, err
+		}
+		return f, nil
+	}
+}
+
+// AsMatching returns a Converter that converts a value to string like AsString
+// and then requires it to match re.
+// Strings that do not match are rejected with ErrCannotConvert, naming both
+// the pattern and the input.
+func AsMatching(re *regexp.Regexp) Converter[string] {
+	return func(v any) (string, error) {
+		s, err := AsString()(v)
+		if err != nil {
+			return "", err
+		}
+		if !re.MatchString(s) {
+			err := newConversionError(v, "string")
+			err.Reason = fmt.Sprintf("%q does not match %q", s, re)
+			return "", err
+		}
+		return s, nil
+	}
+}
+
+// AsMatchingString is like AsMatching, but compiles pattern first.
+// It returns an error if pattern is not a valid regular expression.
+func AsMatchingString(pattern string) (Converter[string], error) {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	return AsMatching(re), nil
+}
+
+// AsBoolLenient returns a Converter that accepts booleans in the loose forms
+// some upstreams send:
+//   - actual bools;
+//   - the numbers 0 and 1 (anything AsFloat64 accepts);
+//   - the strings "true", "t", "yes", "y", "on", "1" and "false", "f", "no",
+//     "n", "off", "0", compared case-insensitively.
+//
+// Anything else is rejected with ErrCannotConvert. AsBool remains the strict
+// default.
+func AsBoolLenient() Converter[bool] {
+	return AsBoolLenientWith(
+		[]string{"true", "t", "yes", "y", "on", "1"},
+		[]string{"false", "f", "no", "n", "off", "0"},
+	)
+}
+
+// AsBoolLenientWith is like AsBoolLenient, but accepts the given truthy and
+// falsy string tokens instead of the standard set.
+func AsBoolLenientWith(truthy, falsy []string) Converter[bool] {
+	return func(v any) (bool, error) {
+		if v == nil {
+			return false, ErrNullValue
+		}
+		switch x := v.(type) {
+		case bool:
+			return x, nil
+		case string:
+			match := func(token string) bool { return strings.EqualFold(token, x) }
+			if slices.ContainsFunc(truthy, match) {
+				return true, nil
+			}
+			if slices.ContainsFunc(falsy, match) {
+				return false, nil
+			}
+			return false, newConversionError(v, "bool")
+		}
+		f, err := AsFloat64()(v)
+		if err != nil {
+			return false, newConversionError(v, "bool")
+		}
+		switch f {
+		case 1:
+			return true, nil
+		case 0:
+			return false, nil
+		}
+		ce := newConversionError(v, "bool")
+		ce.Reason = fmt.Sprintf("number %v is neither 0 nor 1", f)
+		return false, ce
+	}
+}
+
+// AsScientificFloat returns a Converter that parses a numeric string, such as
+// "1.23e-4" or "-5E+10", into a float64.
+// Plain and exponent decimal notation are accepted. Hexadecimal floats, digit
+// separators, and the NaN and Inf tokens are rejected with ErrCannotConvert,
+// as is any non-string input; use AsScientificFloatWith to allow NaN and Inf.
+func AsScientificFloat() Converter[float64] {
+	return AsScientificFloatWith(false)
+}
+
+// AsScientificFloatWith is like AsScientificFloat, but accepts the NaN and
+// Inf tokens understood by strconv.ParseFloat (e.g. "NaN", "+Inf",
+// "infinity") when allowNonFinite is true.
+func AsScientificFloatWith(allowNonFinite bool) Converter[float64] {
+	return func(v any) (float64, error) {
+		s, err := AsString()(v)
+		if err != nil {
+			return 0, err
+		}
+		reject := func(reason string) (float64, error) {
+			ce := newConversionError(v, "float64")
+			ce.Reason = fmt.Sprintf("%q %s", s, reason)
+			return 0, ce
+		}
+		if strings.ContainsAny(s, "xX_") {
+			return reject("is not a decimal number")
+		}
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return reject("is not a number")
+		}
+		if !allowNonFinite && (math.IsNaN(f) || math.IsInf(f, 0)) {
+			return reject("is not finite")
+		}
+		return f, nil
+	}
+}
+
+// AsInt64FromString returns a Converter that parses a decimal string, such as
+// "-9223372036854775808", into an int64 without going through float64, so
+// large IDs keep every digit.
+// It also reads the json.Number and int64 values produced by Parse's
+// NumberModeJSON and NumberModeIntOrFloat exactly, as well as other Go
+// integers. Floats are rejected, since they may already have been rounded,
+// as are other input, non-numeric strings, and values outside the int64
+// range, all with ErrCannotConvert.
+func AsInt64FromString() Converter[int64] {
+	return func(v any) (int64, error) {
+		s, err := integerText(v, "int64")
+		if err != nil {
+			return 0, err
+		}
+		n, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			ce := newConversionError(v, "int64")
+			ce.Reason = parseIntReason(s, err)
+			return 0, ce
+		}
+		return n, nil
+	}
+}
+
+// AsUint64FromString is like AsInt64FromString, but produces a uint64 and so
+// accepts values up to 18446744073709551615 and rejects negative ones.
+func AsUint64FromString() Converter[uint64] {
+	return func(v any) (uint64, error) {
+		s, err := integerText(v, "uint64")
+		if err != nil {
+			return 0, err
+		}
+		n, err := strconv.ParseUint(s, 10, 64)
+		if err != nil {
+			ce := newConversionError(v, "uint64")
+			ce.Reason = parseIntReason(s, err)
+			return 0, ce
+		}
+		return n, nil
+	}
+}
+
+// integerText returns the decimal text of v for AsInt64FromString and
+// AsUint64FromString, which report failures as conversions to target:
+// strings and json.Number as-is, and Go integers formatted exactly.
+func integerText(v any, target string) (string, error) {
+	switch val := v.(type) {
+	case nil:
+		return "", ErrNullValue
+	case string:
+		return val, nil
+	case json.Number:
+		return val.String(), nil
+	}
+	if n, ok := exactInt64(v); ok {
+		return strconv.FormatInt(n, 10), nil
+	}
+	if n, ok := exactUint64(v); ok {
+		return strconv.FormatUint(n, 10), nil
+	}
+	ce := newConversionError(v, target)
+	if _, ok := normalizeNumber(v); ok {
+		ce.Reason = "float values may have lost precision"
+	}
+	return "", ce
+}
+
+// parseIntReason describes why strconv failed to parse s.
+func parseIntReason(s string, err error) string {
+	if errors.Is(err, strconv.ErrRange) {
+		return fmt.Sprintf("%q is out of range", s)
+	}
+	return fmt.Sprintf("%q is not an integer", s)
+}
+
+// AsStringCoerce returns a Converter that renders a JSON scalar as a string.
+// Strings are returned as-is, booleans become "true" or "false", and numbers
+// use the shortest decimal form that round-trips, such as "42" or "0.5".
+// Go integers keep every digit, and a json.Number is returned as its original
+// text, so IDs of any length survive Parse's NumberModeJSON.
+// Null, objects and arrays are rejected.
+func AsStringCoerce() Converter[string] {
+	return func(v any) (string, error) {
+		switch val := v.(type) {
+		case nil:
+			return "", ErrNullValue
+		case string:
+			return val, nil
+		case bool:
+			return strconv.FormatBool(val), nil
+		case json.Number:
+			return val.String(), nil
+		}
+		if n, ok := exactInt64(v); ok {
+			return strconv.FormatInt(n, 10), nil
+		}
+		if n, ok := exactUint64(v); ok {
+			return strconv.FormatUint(n, 10), nil
+		}
+		if f, ok := normalizeNumber(v); ok {
+			return strconv.FormatFloat(f, 'f', -1, 64), nil
+		}
+		return "", newConversionError(v, "string")
+	}
+}
+
+// AsEnum returns a Converter that converts a string to T, a string-based
+// enum type, rejecting any value that is not one of allowed with
+// ErrCannotConvert.
+func AsEnum[T ~string](allowed ...T) Converter[T] {
+	return func(v any) (T, error) {
+		s, err := AsString()(v)
+		if err != nil {
+			return "", err
+		}
+		if !slices.Contains(allowed, T(s)) {
+			ce := newConversionError(v, fmt.Sprintf("%T", T("")))
+			ce.Reason = fmt.Sprintf("%q is not one of %v", s, allowed)
+			return "", ce
+		}
+		return T(s), nil
+	}
+}
@@ -0,0 +1,234 @@
+package jsonflex_test
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/krelinga/go-jsonflex"
+)
+
+func TestAsFiniteFloat64(t *testing.T) {
+	f, err := jsonflex.AsFiniteFloat64()(jsonflex.Number(1.5))
+	if err != nil || f != 1.5 {
+		t.Errorf("expected 1.5, got %v with error %v", f, err)
+	}
+	for _, bad := range []float64{math.NaN(), math.Inf(1), math.Inf(-1)} {
+		if _, err := jsonflex.AsFiniteFloat64()(bad); !errors.Is(err, jsonflex.ErrCannotConvert) {
+			t.Errorf("expected conversion error for %v, got %v", bad, err)
+		}
+	}
+	if _, err := jsonflex.AsFiniteFloat64()(nil); !errors.Is(err, jsonflex.ErrNullValue) {
+		t.Errorf("expected null value error, got %v", err)
+	}
+
+	_, err = jsonflex.AsInt32()(math.NaN())
+	if !errors.Is(err, jsonflex.ErrCannotConvert) || !strings.Contains(err.Error(), "NaN") {
+		t.Errorf("expected NaN-specific conversion error, got %v", err)
+	}
+}
+
+func TestAsMatching(t *testing.T) {
+	slug, err := jsonflex.AsMatchingString(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	got, err := slug("science-fiction")
+	if err != nil || got != "science-fiction" {
+		t.Errorf("expected 'science-fiction', got %q with error %v", got, err)
+	}
+	if _, err := slug("Science Fiction"); !errors.Is(err, jsonflex.ErrCannotConvert) {
+		t.Errorf("expected conversion error, got %v", err)
+	}
+	if _, err := slug(jsonflex.Number(1)); !errors.Is(err, jsonflex.ErrCannotConvert) {
+		t.Errorf("expected conversion error for non-string, got %v", err)
+	}
+
+	slugs, err := jsonflex.AsArray(slug)(jsonflex.Array{"action", "bad slug"})
+	if !errors.Is(err, jsonflex.ErrCannotConvert) || slugs != nil {
+		t.Errorf("expected conversion error from array, got %v with error %v", slugs, err)
+	}
+
+	if _, err := jsonflex.AsMatchingString(`(`); err == nil {
+		t.Error("expected error for invalid pattern")
+	}
+}
+
+func TestAsBoolLenient(t *testing.T) {
+	cases := []struct {
+		input    any
+		expected bool
+	}{
+		{input: true, expected: true},
+		{input: false, expected: false},
+		{input: jsonflex.Number(1), expected: true},
+		{input: jsonflex.Number(0), expected: false},
+		{input: "true", expected: true},
+		{input: "TRUE", expected: true},
+		{input: "t", expected: true},
+		{input: "Yes", expected: true},
+		{input: "y", expected: true},
+		{input: "on", expected: true},
+		{input: "1", expected: true},
+		{input: "false", expected: false},
+		{input: "F", expected: false},
+		{input: "no", expected: false},
+		{input: "N", expected: false},
+		{input: "OFF", expected: false},
+		{input: "0", expected: false},
+	}
+	for _, c := range cases {
+		got, err := jsonflex.AsBoolLenient()(c.input)
+		if err != nil || got != c.expected {
+			t.Errorf("%#v: expected %v, got %v with error %v", c.input, c.expected, got, err)
+		}
+	}
+
+	for _, bad := range []any{"maybe", jsonflex.Number(2), jsonflex.Array{}} {
+		if _, err := jsonflex.AsBoolLenient()(bad); !errors.Is(err, jsonflex.ErrCannotConvert) {
+			t.Errorf("%#v: expected conversion error, got %v", bad, err)
+		}
+	}
+	if _, err := jsonflex.AsBoolLenient()(nil); !errors.Is(err, jsonflex.ErrNullValue) {
+		t.Errorf("expected null value error, got %v", err)
+	}
+
+	custom := jsonflex.AsBoolLenientWith([]string{"ja"}, []string{"nein"})
+	if got, err := custom("JA"); err != nil || !got {
+		t.Errorf("expected true, got %v with error %v", got, err)
+	}
+	if _, err := custom("yes"); !errors.Is(err, jsonflex.ErrCannotConvert) {
+		t.Errorf("expected conversion error for token outside custom set, got %v", err)
+	}
+}
+
+func TestAsScientificFloat(t *testing.T) {
+	cases := []struct {
+		input    string
+		expected float64
+	}{
+		{input: "1.23e-4", expected: 1.23e-4},
+		{input: "-5E+10", expected: -5e10},
+		{input: "6.02e23", expected: 6.02e23},
+		{input: "42", expected: 42},
+		{input: ".5", expected: 0.5},
+	}
+	for _, c := range cases {
+		got, err := jsonflex.AsScientificFloat()(c.input)
+		if err != nil || got != c.expected {
+			t.Errorf("%q: expected %v, got %v with error %v", c.input, c.expected, got, err)
+		}
+	}
+
+	for _, bad := range []any{"", "1e", "abc", "1.2.3", "0x1p-2", "1_000", "NaN", "Inf", "-infinity", jsonflex.Number(1)} {
+		if _, err := jsonflex.AsScientificFloat()(bad); !errors.Is(err, jsonflex.ErrCannotConvert) {
+			t.Errorf("%#v: expected conversion error, got %v", bad, err)
+		}
+	}
+
+	lenient := jsonflex.AsScientificFloatWith(true)
+	if got, err := lenient("+Inf"); err != nil || !math.IsInf(got, 1) {
+		t.Errorf("expected +Inf, got %v with error %v", got, err)
+	}
+	if got, err := lenient("NaN"); err != nil || !math.IsNaN(got) {
+		t.Errorf("expected NaN, got %v with error %v", got, err)
+	}
+}
+
+func TestAsInt64FromString(t *testing.T) {
+	cases := []struct {
+		input    string
+		expected int64
+	}{
+		{input: "0", expected: 0},
+		{input: "9223372036854775807", expected: math.MaxInt64},
+		{input: "-9223372036854775808", expected: math.MinInt64},
+	}
+	for _, c := range cases {
+		got, err := jsonflex.AsInt64FromString()(c.input)
+		if err != nil || got != c.expected {
+			t.Errorf("%q: expected %d, got %d with error %v", c.input, c.expected, got, err)
+		}
+	}
+
+	for _, bad := range []any{"9223372036854775808", "-9223372036854775809", "12abc", "1.5", "", jsonflex.Number(1)} {
+		if _, err := jsonflex.AsInt64FromString()(bad); !errors.Is(err, jsonflex.ErrCannotConvert) {
+			t.Errorf("%#v: expected conversion error, got %v", bad, err)
+		}
+	}
+	_, err := jsonflex.AsInt64FromString()("9223372036854775808")
+	if err == nil || !strings.Contains(err.Error(), "out of range") {
+		t.Errorf("expected out of range error, got %v", err)
+	}
+	_, err = jsonflex.AsInt64FromString()(jsonflex.Number(1))
+	if err == nil || !strings.Contains(err.Error(), "cannot convert float64 to int64") {
+		t.Errorf("expected error naming int64, got %v", err)
+	}
+	_, err = jsonflex.AsUint64FromString()(jsonflex.Number(1))
+	if err == nil || !strings.Contains(err.Error(), "cannot convert float64 to uint64") {
+		t.Errorf("expected error naming uint64, got %v", err)
+	}
+}
+
+func TestAsUint64FromString(t *testing.T) {
+	got, err := jsonflex.AsUint64FromString()("18446744073709551615")
+	if err != nil || got != math.MaxUint64 {
+		t.Errorf("expected max uint64, got %d with error %v", got, err)
+	}
+	for _, bad := range []any{"18446744073709551616", "-1", "abc"} {
+		if _, err := jsonflex.AsUint64FromString()(bad); !errors.Is(err, jsonflex.ErrCannotConvert) {
+			t.Errorf("%#v: expected conversion error, got %v", bad, err)
+		}
+	}
+}
+
+func TestAsStringCoerce(t *testing.T) {
+	cases := []struct {
+		input    any
+		expected string
+	}{
+		{input: "text", expected: "text"},
+		{input: true, expected: "true"},
+		{input: false, expected: "false"},
+		{input: jsonflex.Number(42), expected: "42"},
+		{input: jsonflex.Number(0.5), expected: "0.5"},
+		{input: jsonflex.Number(1e21), expected: "1000000000000000000000"},
+		{input: 7, expected: "7"},
+		{input: uint64(18446744073709551615), expected: "18446744073709551615"},
+		{input: json.Number("-9223372036854775809"), expected: "-9223372036854775809"},
+		{input: json.Number("123456789012345678901234567890"), expected: "123456789012345678901234567890"},
+		{input: json.Number("1.50"), expected: "1.50"},
+	}
+	for _, c := range cases {
+		got, err := jsonflex.AsStringCoerce()(c.input)
+		if err != nil || got != c.expected {
+			t.Errorf("%#v: expected %q, got %q with error %v", c.input, c.expected, got, err)
+		}
+	}
+
+	if _, err := jsonflex.AsStringCoerce()(nil); !errors.Is(err, jsonflex.ErrNullValue) {
+		t.Errorf("expected null value error, got %v", err)
+	}
+	if _, err := jsonflex.AsStringCoerce()(jsonflex.Array{}); !errors.Is(err, jsonflex.ErrCannotConvert) {
+		t.Errorf("expected conversion error, got %v", err)
+	}
+}
+
+type Status string
+
+func TestAsEnum(t *testing.T) {
+	conv := jsonflex.AsEnum[Status]("active", "inactive")
+	got, err := conv("active")
+	if err != nil || got != "active" {
+		t.Errorf("expected active, got %q with error %v", got, err)
+	}
+	_, err = conv("deleted")
+	if !errors.Is(err, jsonflex.ErrCannotConvert) || !strings.Contains(err.Error(), `"deleted" is not one of [active inactive]`) {
+		t.Errorf("expected enum error, got %v", err)
+	}
+	if _, err := conv(jsonflex.Number(1)); !errors.Is(err, jsonflex.ErrCannotConvert) {
+		t.Errorf("expected conversion error, got %v", err)
+	}
+}
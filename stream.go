@@ -0,0 +1,145 @@
+package jsonflex
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// TokenHandler receives the events of StreamParse.
+//
+// Every callback gets the path of the value it concerns, as object keys and
+// decimal array indices leading to it from the root; the root value has an
+// empty path. The path slice is reused between calls, so handlers that keep
+// it must copy it, e.g. with slices.Clone.
+//
+// Returning ErrStop from any callback ends parsing early without an error;
+// any other error aborts StreamParse with that error.
+type TokenHandler interface {
+	// OnObjectStart is called at the opening brace of an object.
+	OnObjectStart(path []string) error
+	// OnKey is called for each key of the object at path, before its value.
+	OnKey(path []string, key string) error
+	// OnObjectEnd is called at the closing brace of the object at path.
+	OnObjectEnd(path []string) error
+	// OnArrayStart is called at the opening bracket of an array.
+	OnArrayStart(path []string) error
+	// OnArrayEnd is called at the closing bracket of the array at path.
+	OnArrayEnd(path []string) error
+	// OnValue is called for each scalar: a string, float64, bool, or nil.
+	OnValue(path []string, v any) error
+}
+
+// NopTokenHandler implements every TokenHandler callback as a no-op. Embed it
+// to implement only the callbacks of interest.
+type NopTokenHandler struct{}
+
+func (NopTokenHandler) OnObjectStart([]string) error { return nil }
+func (NopTokenHandler) OnKey([]string, string) error { return nil }
+func (NopTokenHandler) OnObjectEnd([]string) error   { return nil }
+func (NopTokenHandler) OnArrayStart([]string) error  { return nil }
+func (NopTokenHandler) OnArrayEnd([]string) error    { return nil }
+func (NopTokenHandler) OnValue([]string, any) error  { return nil }
+
+// StreamParse reads JSON from r token by token and reports its structure to
+// handler, without building the decoded tree in memory. This suits extracting
+// a few values from very large documents: scalars are reported with their
+// path, and returning ErrStop once the wanted values are found stops reading.
+//
+// Several consecutive top-level values, as in NDJSON, are each reported from
+// an empty path. Malformed or truncated input is an error.
+func StreamParse(r io.Reader, handler TokenHandler) error {
+	err := streamParse(json.NewDecoder(r), handler)
+	if errors.Is(err, ErrStop) {
+		return nil
+	}
+	return err
+}
+
+type streamFrame struct {
+	object  bool
+	wantKey bool
+	next    int
+}
+
+func streamParse(dec *json.Decoder, handler TokenHandler) error {
+	var path []string
+	var stack []*streamFrame
+	// endValue is called once a value is complete, to leave its path segment.
+	endValue := func() {
+		if len(stack) == 0 {
+			return
+		}
+		path = path[:len(path)-1]
+		if top := stack[len(stack)-1]; top.object {
+			top.wantKey = true
+		}
+	}
+	for {
+		tok, err := dec.Token()
+		if errors.Is(err, io.EOF) {
+			if len(stack) > 0 {
+				return io.ErrUnexpectedEOF
+			}
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		var top *streamFrame
+		if len(stack) > 0 {
+			top = stack[len(stack)-1]
+		}
+		if top != nil && top.object && top.wantKey {
+			if tok == json.Delim('}') {
+				stack = stack[:len(stack)-1]
+				if err := handler.OnObjectEnd(path); err != nil {
+					return err
+				}
+				endValue()
+				continue
+			}
+			key, ok := tok.(string)
+			if !ok {
+				return fmt.Errorf("unexpected token %v, expected an object key", tok)
+			}
+			if err := handler.OnKey(path, key); err != nil {
+				return err
+			}
+			path = append(path, key)
+			top.wantKey = false
+			continue
+		}
+		if tok == json.Delim(']') {
+			stack = stack[:len(stack)-1]
+			if err := handler.OnArrayEnd(path); err != nil {
+				return err
+			}
+			endValue()
+			continue
+		}
+		if top != nil && !top.object {
+			path = append(path, strconv.Itoa(top.next))
+			top.next++
+		}
+		switch tok {
+		case json.Delim('{'):
+			if err := handler.OnObjectStart(path); err != nil {
+				return err
+			}
+			stack = append(stack, &streamFrame{object: true, wantKey: true})
+		case json.Delim('['):
+			if err := handler.OnArrayStart(path); err != nil {
+				return err
+			}
+			stack = append(stack, &streamFrame{})
+		default:
+			if err := handler.OnValue(path, tok); err != nil {
+				return err
+			}
+			endValue()
+		}
+	}
+}
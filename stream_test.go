@@ -0,0 +1,122 @@
+package jsonflex_test
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/krelinga/go-jsonflex"
+)
+
+type recordingHandler struct {
+	events []string
+}
+
+func (h *recordingHandler) record(event string, path []string) error {
+	h.events = append(h.events, fmt.Sprintf("%s /%s", event, strings.Join(path, "/")))
+	return nil
+}
+
+func (h *recordingHandler) OnObjectStart(path []string) error { return h.record("{", path) }
+func (h *recordingHandler) OnObjectEnd(path []string) error   { return h.record("}", path) }
+func (h *recordingHandler) OnArrayStart(path []string) error  { return h.record("[", path) }
+func (h *recordingHandler) OnArrayEnd(path []string) error    { return h.record("]", path) }
+
+func (h *recordingHandler) OnKey(path []string, key string) error {
+	return h.record("key "+key, path)
+}
+
+func (h *recordingHandler) OnValue(path []string, v any) error {
+	return h.record(fmt.Sprintf("value %v", v), path)
+}
+
+func TestStreamParse(t *testing.T) {
+	input := `{"results": [{"id": 1, "tags": []}, null], "total": 2, "ok": true}`
+	var h recordingHandler
+	if err := jsonflex.StreamParse(strings.NewReader(input), &h); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := []string{
+		"{ /",
+		"key results /",
+		"[ /results",
+		"{ /results/0",
+		"key id /results/0",
+		"value 1 /results/0/id",
+		"key tags /results/0",
+		"[ /results/0/tags",
+		"] /results/0/tags",
+		"} /results/0",
+		"value <nil> /results/1",
+		"] /results",
+		"key total /",
+		"value 2 /total",
+		"key ok /",
+		"value true /ok",
+		"} /",
+	}
+	if diff := cmp.Diff(expected, h.events); diff != "" {
+		t.Errorf("mismatch (-want +got):\n%s", diff)
+	}
+
+	h = recordingHandler{}
+	if err := jsonflex.StreamParse(strings.NewReader("1\n\"x\"\n"), &h); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if diff := cmp.Diff([]string{"value 1 /", "value x /"}, h.events); diff != "" {
+		t.Errorf("mismatch (-want +got):\n%s", diff)
+	}
+
+	for _, bad := range []string{`{"a": 1`, `[1, 2`, `{"a" 1}`, `]`} {
+		if err := jsonflex.StreamParse(strings.NewReader(bad), jsonflex.NopTokenHandler{}); err == nil {
+			t.Errorf("%s: expected error", bad)
+		}
+	}
+}
+
+// totalFinder records the value of the top-level "total" field and stops.
+type totalFinder struct {
+	jsonflex.NopTokenHandler
+	total any
+}
+
+func (f *totalFinder) OnValue(path []string, v any) error {
+	if len(path) == 1 && path[0] == "total" {
+		f.total = v
+		return jsonflex.ErrStop
+	}
+	return nil
+}
+
+type failingReader struct{}
+
+func (failingReader) Read([]byte) (int, error) { return 0, errors.New("read past the stop point") }
+
+func TestStreamParseStop(t *testing.T) {
+	// The reader fails after the prefix, so parsing only succeeds if it stops
+	// as soon as the value is found.
+	r := io.MultiReader(strings.NewReader(`{"total": 42, "results": [`), failingReader{})
+	var f totalFinder
+	if err := jsonflex.StreamParse(r, &f); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if f.total != float64(42) {
+		t.Errorf("expected total 42, got %v", f.total)
+	}
+
+	wantErr := errors.New("handler failed")
+	h := errorHandler{err: wantErr}
+	if err := jsonflex.StreamParse(strings.NewReader(`[1]`), h); !errors.Is(err, wantErr) {
+		t.Errorf("expected handler error, got %v", err)
+	}
+}
+
+type errorHandler struct {
+	jsonflex.NopTokenHandler
+	err error
+}
+
+func (h errorHandler) OnValue([]string, any) error { return h.err }
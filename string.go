@@ -1,10 +1,13 @@
 package jsonflex
 
 import (
+	"bytes"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"reflect"
 	"slices"
+	"strconv"
 	"strings"
 )
 
@@ -12,67 +15,372 @@ import (
 //
 // Only supports the following types:
 // - All types rooted in Object.
-// - JSON basic types (bool, float64, int32, string)
+// - JSON basic types (bool, string) and Go integer and float types, or named types based on them.
 // - Slices of any other supported type.
+//
+// Objects are rendered through their accessor methods, emitted in
+// alphabetical order by method name so output is deterministic; see
+// WithKeyOrder to customize the order. Strings are quoted with Go syntax (as by strconv.Quote), so control
+// characters such as newlines and tabs are always escaped. Use StringWith and
+// WithJSONQuoting for JSON-style string quoting instead.
+//
+// String is built on WalkValue; implement a Visitor for other formats.
 func String(v any) string {
-	return innerString(reflect.ValueOf(v))
+	return renderer{}.render(v)
+}
+
+// StringCompact is like String, but renders the value on a single line with
+// no indentation, e.g. {Adult: false, GenreIDs: [1, 2, 3], Title: "x"}.
+// This is useful for log pipelines that cannot handle multi-line values.
+func StringCompact(v any) string {
+	return renderer{compact: true}.render(v)
+}
+
+// StringOption configures the rendering performed by StringWith.
+type StringOption func(*renderer)
+
+// StringWith is like String, but applies the given options.
+func StringWith(v any, opts ...StringOption) string {
+	r := renderer{}
+	for _, opt := range opts {
+		opt(&r)
+	}
+	return r.render(v)
 }
 
-func indent(in string) string {
-	return strings.ReplaceAll(in, "\n", "\n  ")
+// WithShowAbsent controls whether fields whose accessor returns
+// ErrFieldNotFound are rendered as "Key: <absent>" instead of being omitted.
+// This helps diagnose why an optional field did not populate.
+func WithShowAbsent(show bool) StringOption {
+	return func(r *renderer) {
+		r.showAbsent = show
+	}
 }
 
-func innerString(v reflect.Value) string {
+// WithJSONQuoting controls whether strings are quoted using JSON syntax
+// instead of Go syntax. The two differ for control and non-printable
+// characters, e.g. \a versus \u0007; HTML-sensitive characters such as '<'
+// and '&' are not escaped in either form.
+func WithJSONQuoting(enabled bool) StringOption {
+	return func(r *renderer) {
+		r.jsonQuoting = enabled
+	}
+}
+
+// WithNumberFormat sets the function used to render float values, e.g.
+// func(f float64) string { return strconv.FormatFloat(f, 'f', -1, 64) } to
+// avoid scientific notation. The default is the %v verb. Integer values are
+// not affected, since %v never renders them in scientific notation.
+func WithNumberFormat(format func(float64) string) StringOption {
+	return func(r *renderer) {
+		r.numberFormat = format
+	}
+}
+
+// WithKeyOrder sets the comparison function, in the style of strings.Compare,
+// used to order the rendered fields of an object by method name. The default
+// is alphabetical order. A stable sort is used, so fields that compare equal
+// stay in alphabetical order.
+func WithKeyOrder(cmp func(a, b string) int) StringOption {
+	return func(r *renderer) {
+		r.keyOrder = cmp
+	}
+}
+
+// WithRenderer registers fn to render every value whose concrete type is t,
+// bypassing the default rendering for that type. Unlike an interface, this
+// works for types the caller does not control, e.g. rendering a money type as
+// "$12.34". The option may be given several times for different types.
+func WithRenderer(t reflect.Type, fn func(any) string) StringOption {
+	return func(r *renderer) {
+		if r.renderers == nil {
+			r.renderers = make(map[reflect.Type]func(any) string)
+		}
+		r.renderers[t] = fn
+	}
+}
+
+// WithIndentString sets the string used for each level of indentation, such
+// as "\t" or four spaces. The default, also used when s is empty, is two
+// spaces. It has no effect on StringCompact-style output.
+func WithIndentString(s string) StringOption {
+	return func(r *renderer) {
+		r.indentString = s
+	}
+}
+
+// WithSummarizeBelow renders objects nested more than depth objects deep as
+// a one-line summary such as {...3 fields} instead of expanding them, where
+// the count is the number of fields in the underlying Object. The top-level
+// object is at depth 1, and arrays do not add depth, so with a depth of 1 only
+// the outermost object is expanded. A depth below 1 disables summarizing,
+// which is the default.
+func WithSummarizeBelow(depth int) StringOption {
+	return func(r *renderer) {
+		r.summarizeBelow = depth
+	}
+}
+
+// WithOnlyFields renders only the object fields whose method name, the key
+// shown in the output, is one of keys. It applies to nested objects too, and
+// may be given several times to add keys. It cannot be combined with
+// WithExcludeFields; if both are given, the output is an error message.
+func WithOnlyFields(keys ...string) StringOption {
+	return func(r *renderer) {
+		r.onlyFields = addKeys(r.onlyFields, keys)
+	}
+}
+
+// WithExcludeFields omits the object fields whose method name is one of keys,
+// at any depth. It cannot be combined with WithOnlyFields.
+func WithExcludeFields(keys ...string) StringOption {
+	return func(r *renderer) {
+		r.excludeFields = addKeys(r.excludeFields, keys)
+	}
+}
+
+func addKeys(set map[string]bool, keys []string) map[string]bool {
+	if set == nil {
+		set = make(map[string]bool, len(keys))
+	}
+	for _, key := range keys {
+		set[key] = true
+	}
+	return set
+}
+
+func indent(in, unit string) string {
+	return strings.ReplaceAll(in, "\n", "\n"+unit)
+}
+
+// renderer holds the settings shared by String, StringCompact, and StringWith.
+type renderer struct {
+	compact        bool
+	showAbsent     bool
+	jsonQuoting    bool
+	numberFormat   func(float64) string
+	keyOrder       func(a, b string) int
+	renderers      map[reflect.Type]func(any) string
+	indentString   string
+	summarizeBelow int
+	onlyFields     map[string]bool
+	excludeFields  map[string]bool
+}
+
+// wrap lays out the already-rendered entries of an object or array between
+// the start and end delimiters.
+func (r renderer) wrap(start, end string, entries []string) string {
+	if r.compact {
+		return start + strings.Join(entries, ", ") + end
+	}
+	unit := r.indentString
+	if unit == "" {
+		unit = "  "
+	}
 	sb := strings.Builder{}
-	switch v.Kind() {
-	case reflect.Map:
-		sb.WriteString("{\n")
-		methods := make([]int, v.NumMethod())
-		for methodNum := range v.Type().NumMethod() {
-			methods[methodNum] = methodNum
+	sb.WriteString(start + "\n")
+	for _, entry := range entries {
+		sb.WriteString(fmt.Sprintf("%s%s,\n", unit, indent(entry, unit)))
+	}
+	sb.WriteString(end)
+	return sb.String()
+}
+
+func (r renderer) render(v any) string {
+	if r.onlyFields != nil && r.excludeFields != nil {
+		return "error: WithOnlyFields and WithExcludeFields cannot be combined"
+	}
+	vis := &renderVisitor{renderer: r}
+	WalkValue(v, vis)
+	return vis.out
+}
+
+// renderVisitor is the Visitor behind String. Each object or array being
+// rendered has a frame on the stack collecting its rendered entries.
+type renderVisitor struct {
+	renderer
+	stack []*renderFrame
+	out   string
+}
+
+type renderFrame struct {
+	object  bool
+	entries []renderEntry
+	// label is the field name or index of the value being visited.
+	label string
+}
+
+type renderEntry struct {
+	label, text string
+}
+
+// emit records the rendering of a complete value.
+func (r *renderVisitor) emit(text string) {
+	if len(r.stack) == 0 {
+		r.out = text
+		return
+	}
+	top := r.stack[len(r.stack)-1]
+	top.entries = append(top.entries, renderEntry{label: top.label, text: text})
+}
+
+// custom renders v with a renderer registered by WithRenderer, if any.
+func (r *renderVisitor) custom(v any) bool {
+	if v == nil {
+		return false
+	}
+	fn, ok := r.renderers[reflect.TypeOf(v)]
+	if ok {
+		r.emit(fn(v))
+	}
+	return ok
+}
+
+func (r *renderVisitor) EnterObject(v any) bool {
+	if r.custom(v) {
+		return false
+	}
+	depth := 0
+	for _, frame := range r.stack {
+		if frame.object {
+			depth++
 		}
-		slices.SortFunc(methods, func(a, b int) int {
-			aName := v.Type().Method(a).Name
-			bName := v.Type().Method(b).Name
-			return strings.Compare(aName, bName)
+	}
+	if r.summarizeBelow >= 1 && depth >= r.summarizeBelow {
+		if n := reflect.ValueOf(v).Len(); n == 1 {
+			r.emit("{...1 field}")
+		} else {
+			r.emit(fmt.Sprintf("{...%d fields}", n))
+		}
+		return false
+	}
+	r.stack = append(r.stack, &renderFrame{object: true})
+	return true
+}
+
+func (r *renderVisitor) ObjectField(name string, err error) bool {
+	if (r.onlyFields != nil && !r.onlyFields[name]) || r.excludeFields[name] {
+		return false
+	}
+	top := r.stack[len(r.stack)-1]
+	top.label = name
+	switch {
+	case err == nil:
+		return true
+	case errors.Is(err, ErrNullValue):
+		r.emit("null")
+	case errors.Is(err, ErrFieldNotFound):
+		if r.showAbsent {
+			r.emit("<absent>")
+		}
+	default:
+		r.emit(fmt.Sprintf("error: %s", err))
+	}
+	return false
+}
+
+func (r *renderVisitor) LeaveObject(any) {
+	frame := r.pop()
+	if r.keyOrder != nil {
+		slices.SortStableFunc(frame.entries, func(a, b renderEntry) int {
+			return r.keyOrder(a.label, b.label)
 		})
-		for _, methodNum := range methods {
-			method := v.Type().Method(methodNum)
-			if method.Type.NumIn() != 1 {
-				continue
-			}
-			if method.Type.NumOut() != 2 {
-				continue
-			}
-			if method.Type.Out(1) != reflect.TypeFor[error]() {
-				continue
-			}
-			outs := method.Func.Call([]reflect.Value{v})
-			var outString string
-			if outs[1].IsNil() {
-				outString = indent(innerString(outs[0]))
-			} else if errors.Is(outs[1].Interface().(error), ErrNullValue) {
-				outString = "null"
-			} else if errors.Is(outs[1].Interface().(error), ErrFieldNotFound) {
-				continue
-			} else {
-				outString = fmt.Sprintf("error: %s", outs[1].Interface())
+	}
+	entries := make([]string, len(frame.entries))
+	for i, entry := range frame.entries {
+		entries[i] = fmt.Sprintf("%s: %s", entry.label, entry.text)
+	}
+	r.emit(r.wrap("{", "}", entries))
+}
+
+func (r *renderVisitor) EnterArray(v any) bool {
+	if r.custom(v) {
+		return false
+	}
+	r.stack = append(r.stack, &renderFrame{})
+	return true
+}
+
+func (r *renderVisitor) Element(i int) {
+	r.stack[len(r.stack)-1].label = strconv.Itoa(i)
+}
+
+func (r *renderVisitor) LeaveArray(any) {
+	frame := r.pop()
+	entries := make([]string, len(frame.entries))
+	for i, entry := range frame.entries {
+		if r.compact {
+			entries[i] = entry.text
+		} else {
+			entries[i] = fmt.Sprintf("%s: %s", entry.label, entry.text)
+		}
+	}
+	r.emit(r.wrap("[", "]", entries))
+}
+
+func (r *renderVisitor) Scalar(v any) {
+	if r.custom(v) {
+		return
+	}
+	r.emit(r.scalar(v))
+}
+
+func (r *renderVisitor) pop() *renderFrame {
+	top := r.stack[len(r.stack)-1]
+	r.stack = r.stack[:len(r.stack)-1]
+	return top
+}
+
+func (r renderer) scalar(val any) string {
+	if val == nil {
+		return "null"
+	}
+	// A json.Number from Parse is a string kind, but renders as a number.
+	// Integer literals are left alone, like int64 values.
+	if n, ok := val.(json.Number); ok {
+		if _, err := n.Int64(); err != nil && r.numberFormat != nil {
+			if f, err := n.Float64(); err == nil {
+				return r.numberFormat(f)
 			}
-			sb.WriteString(fmt.Sprintf("  %s: %s,\n", method.Name, outString))
 		}
-		sb.WriteString("}")
-	case reflect.Slice:
-		sb.WriteString("[\n")
-		for i := 0; i < v.Len(); i++ {
-			sb.WriteString(fmt.Sprintf("  %d: %s,\n", i, indent(innerString(v.Index(i)))))
+		return n.String()
+	}
+	v := reflect.ValueOf(val)
+	switch v.Kind() {
+	// Scalars are read through their Kind, so named types such as
+	// `type Rating int` render as their underlying value even if they
+	// implement fmt.Stringer.
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(v.Uint(), 10)
+	case reflect.Float32:
+		if r.numberFormat != nil {
+			return r.numberFormat(v.Float())
+		}
+		return fmt.Sprintf("%v", float32(v.Float()))
+	case reflect.Float64:
+		if r.numberFormat != nil {
+			return r.numberFormat(v.Float())
 		}
-		sb.WriteString("]")
-	case reflect.Bool, reflect.Int32, reflect.Float64:
-		sb.WriteString(fmt.Sprintf("%v", v.Interface()))
+		return fmt.Sprintf("%v", v.Float())
 	case reflect.String:
-		sb.WriteString(fmt.Sprintf("%q", v.Interface()))
+		if r.jsonQuoting {
+			return jsonQuote(v.String())
+		}
+		return strconv.Quote(v.String())
 	default:
-		sb.WriteString(fmt.Sprintf("unsupported type: %s", v.Type()))
+		return fmt.Sprintf("unsupported type: %s", v.Type())
 	}
-	return sb.String()
+}
+
+func jsonQuote(s string) string {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	// Encoding a string cannot fail.
+	_ = enc.Encode(s)
+	return strings.TrimSuffix(buf.String(), "\n")
 }
@@ -1,12 +1,67 @@
 package jsonflex_test
 
 import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/krelinga/go-jsonflex"
 )
 
+type Stats jsonflex.Object
+
+func (s Stats) Views() (int64, error) {
+	v, err := jsonflex.GetField(s, "views", jsonflex.AsFloat64())
+	return int64(v), err
+}
+
+func (s Stats) Likes() (uint32, error) {
+	v, err := jsonflex.GetField(s, "likes", jsonflex.AsFloat64())
+	return uint32(v), err
+}
+
+type Rating int
+
+// String is deliberately different from the numeric value, to check that the
+// renderer does not use it.
+func (r Rating) String() string {
+	return fmt.Sprintf("%d stars", int(r))
+}
+
+type Review jsonflex.Object
+
+func (r Review) Rating() (Rating, error) {
+	v, err := jsonflex.GetField(r, "rating", jsonflex.AsInt32())
+	return Rating(v), err
+}
+
+func (r Review) Status() (Status, error) {
+	v, err := jsonflex.GetField(r, "status", jsonflex.AsString())
+	return Status(v), err
+}
+
+func (r Review) Score() (Score, error) {
+	v, err := jsonflex.GetField(r, "score", jsonflex.AsFloat64())
+	return Score(v), err
+}
+
+type Score float32
+
+func TestStringNamedScalars(t *testing.T) {
+	review := Review{"rating": jsonflex.Number(4), "status": "published", "score": jsonflex.Number(0.1)}
+	expected := `{Rating: 4, Score: 0.1, Status: "published"}`
+	if diff := cmp.Diff(expected, jsonflex.StringCompact(review)); diff != "" {
+		t.Errorf("mismatch (-want +got):\n%s", diff)
+	}
+	if got := jsonflex.StringCompact([]Rating{1, 5}); got != "[1, 5]" {
+		t.Errorf("expected [1, 5], got %s", got)
+	}
+}
+
 func TestString(t *testing.T) {
 	cases := []struct {
 		name     string
@@ -93,11 +148,22 @@ func TestString(t *testing.T) {
   1: 2.2,
   2: 3.3,
 ]`,
+		},
+		{
+			name: "Wider Numeric Kinds",
+			input: Stats{
+				"views": jsonflex.Number(10000000000),
+				"likes": jsonflex.Number(42),
+			},
+			expected: `{
+  Likes: 42,
+  Views: 10000000000,
+}`,
 		},
 		{
 			name:     "Unsupported type",
-			input:    int(42),
-			expected: "unsupported type: int",
+			input:    complex(1, 2),
+			expected: "unsupported type: complex128",
 		},
 	}
 
@@ -110,3 +176,330 @@ func TestString(t *testing.T) {
 		})
 	}
 }
+
+func TestStringCompact(t *testing.T) {
+	cases := []struct {
+		name     string
+		input    any
+		expected string
+	}{
+		{
+			name: "Direct Object",
+			input: Movie{
+				"adult":     false,
+				"title":     "x",
+				"genre_ids": jsonflex.Array{jsonflex.Number(1), jsonflex.Number(2), jsonflex.Number(3)},
+			},
+			expected: `{Adult: false, GenreIDs: [1, 2, 3], Title: "x"}`,
+		},
+		{
+			name: "Nested Objects",
+			input: Movie{
+				"title": nil,
+				"genres": jsonflex.Array{
+					jsonflex.Object{"id": jsonflex.Number(28), "name": "Action"},
+				},
+			},
+			expected: `{Genres: [{ID: 28, Name: "Action"}], Title: null}`,
+		},
+		{
+			name:     "Empty Array",
+			input:    []string{},
+			expected: `[]`,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got := jsonflex.StringCompact(c.input)
+			if diff := cmp.Diff(c.expected, got); diff != "" {
+				t.Errorf("mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestStringWithShowAbsent(t *testing.T) {
+	movie := Movie{"title": "Inception", "adult": nil}
+	expected := `{
+  Adult: null,
+  GenreIDs: <absent>,
+  Genres: <absent>,
+  ID: <absent>,
+  Title: "Inception",
+}`
+	if diff := cmp.Diff(expected, jsonflex.StringWith(movie, jsonflex.WithShowAbsent(true))); diff != "" {
+		t.Errorf("mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(jsonflex.String(movie), jsonflex.StringWith(movie, jsonflex.WithShowAbsent(false))); diff != "" {
+		t.Errorf("mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestStringQuoting(t *testing.T) {
+	input := []string{"line1\nline2\t\"quoted\"", "<a&b> é \u0007"}
+
+	expected := `[
+  0: "line1\nline2\t\"quoted\"",
+  1: "<a&b> é \a",
+]`
+	if diff := cmp.Diff(expected, jsonflex.String(input)); diff != "" {
+		t.Errorf("mismatch (-want +got):\n%s", diff)
+	}
+
+	expected = `[
+  0: "line1\nline2\t\"quoted\"",
+  1: "<a&b> é \u0007",
+]`
+	if diff := cmp.Diff(expected, jsonflex.StringWith(input, jsonflex.WithJSONQuoting(true))); diff != "" {
+		t.Errorf("mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestStringWithNumberFormat(t *testing.T) {
+	input := []float64{1e6, 0.5}
+	expected := `[
+  0: 1e+06,
+  1: 0.5,
+]`
+	if diff := cmp.Diff(expected, jsonflex.String(input)); diff != "" {
+		t.Errorf("mismatch (-want +got):\n%s", diff)
+	}
+
+	plain := func(f float64) string { return strconv.FormatFloat(f, 'f', -1, 64) }
+	expected = `[
+  0: 1000000,
+  1: 0.5,
+]`
+	if diff := cmp.Diff(expected, jsonflex.StringWith(input, jsonflex.WithNumberFormat(plain))); diff != "" {
+		t.Errorf("mismatch (-want +got):\n%s", diff)
+	}
+
+	// Integers are unaffected whether Parse produced json.Number or int64.
+	fixed := func(f float64) string { return strconv.FormatFloat(f, 'f', 2, 64) }
+	for _, v := range []any{json.Number("5"), int64(5)} {
+		if got := jsonflex.StringWith(v, jsonflex.WithNumberFormat(fixed)); got != "5" {
+			t.Errorf("%T: expected 5, got %s", v, got)
+		}
+	}
+	if got := jsonflex.StringWith(json.Number("1.5"), jsonflex.WithNumberFormat(fixed)); got != "1.50" {
+		t.Errorf("expected 1.50, got %s", got)
+	}
+}
+
+func TestStringWithKeyOrder(t *testing.T) {
+	movie := Movie{"adult": false, "title": "Inception", "id": jsonflex.Number(1)}
+	declared := []string{"ID", "Title", "Adult"}
+	order := func(a, b string) int {
+		return slices.Index(declared, a) - slices.Index(declared, b)
+	}
+	expected := `{
+  ID: 1,
+  Title: "Inception",
+  Adult: false,
+}`
+	got := jsonflex.StringWith(movie, jsonflex.WithKeyOrder(order))
+	if diff := cmp.Diff(expected, got); diff != "" {
+		t.Errorf("mismatch (-want +got):\n%s", diff)
+	}
+
+	// The default order is alphabetical and stable across runs.
+	for range 10 {
+		if got := jsonflex.StringCompact(movie); got != `{Adult: false, ID: 1, Title: "Inception"}` {
+			t.Fatalf("unexpected default order: %s", got)
+		}
+	}
+}
+
+type Cents int64
+
+type Product jsonflex.Object
+
+func (p Product) Name() (string, error) {
+	return jsonflex.GetField(p, "name", jsonflex.AsString())
+}
+
+func (p Product) Price() (Cents, error) {
+	v, err := jsonflex.GetField(p, "price", jsonflex.AsInt32())
+	return Cents(v), err
+}
+
+func (p Product) Tags() ([]string, error) {
+	return jsonflex.GetField(p, "tags", jsonflex.AsArray(jsonflex.AsString()))
+}
+
+func TestStringWithRenderer(t *testing.T) {
+	product := Product{"name": "Widget", "price": jsonflex.Number(1234), "tags": jsonflex.Array{"a", "b"}}
+	money := jsonflex.WithRenderer(reflect.TypeFor[Cents](), func(v any) string {
+		c := v.(Cents)
+		return fmt.Sprintf("$%d.%02d", c/100, c%100)
+	})
+	tags := jsonflex.WithRenderer(reflect.TypeFor[[]string](), func(v any) string {
+		return fmt.Sprintf("%d tags", len(v.([]string)))
+	})
+	expected := `{
+  Name: "Widget",
+  Price: $12.34,
+  Tags: 2 tags,
+}`
+	if diff := cmp.Diff(expected, jsonflex.StringWith(product, money, tags)); diff != "" {
+		t.Errorf("mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestStringWithIndentString(t *testing.T) {
+	movie := Movie{
+		"title": "x",
+		"genres": jsonflex.Array{
+			jsonflex.Object{"id": jsonflex.Number(28)},
+		},
+	}
+	expected := "{\n\tGenres: [\n\t\t0: {\n\t\t\tID: 28,\n\t\t},\n\t],\n\tTitle: \"x\",\n}"
+	if diff := cmp.Diff(expected, jsonflex.StringWith(movie, jsonflex.WithIndentString("\t"))); diff != "" {
+		t.Errorf("mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(jsonflex.String(movie), jsonflex.StringWith(movie, jsonflex.WithIndentString(""))); diff != "" {
+		t.Errorf("expected empty indent string to keep the default (-want +got):\n%s", diff)
+	}
+}
+
+type Library jsonflex.Object
+
+func (l Library) Name() (string, error) {
+	return jsonflex.GetField(l, "name", jsonflex.AsString())
+}
+
+func (l Library) Shelves() ([]Shelf, error) {
+	return jsonflex.GetField(l, "shelves", jsonflex.AsArray(jsonflex.AsObject[Shelf]()))
+}
+
+type Shelf jsonflex.Object
+
+func (s Shelf) Label() (string, error) {
+	return jsonflex.GetField(s, "label", jsonflex.AsString())
+}
+
+func (s Shelf) Featured() (Genre, error) {
+	return jsonflex.GetField(s, "featured", jsonflex.AsObject[Genre]())
+}
+
+func TestStringWithSummarizeBelow(t *testing.T) {
+	library := Library{
+		"name": "Central",
+		"shelves": jsonflex.Array{
+			jsonflex.Object{
+				"label":    "A",
+				"featured": jsonflex.Object{"id": jsonflex.Number(28), "name": "Action"},
+			},
+			jsonflex.Object{"label": "B"},
+		},
+	}
+	cases := []struct {
+		name     string
+		depth    int
+		expected string
+	}{
+		{
+			name:  "Depth 1",
+			depth: 1,
+			expected: `{
+  Name: "Central",
+  Shelves: [
+    0: {...2 fields},
+    1: {...1 field},
+  ],
+}`,
+		},
+		{
+			name:  "Depth 2",
+			depth: 2,
+			expected: `{
+  Name: "Central",
+  Shelves: [
+    0: {
+      Featured: {...2 fields},
+      Label: "A",
+    },
+    1: {
+      Label: "B",
+    },
+  ],
+}`,
+		},
+		{
+			name:     "Disabled",
+			depth:    0,
+			expected: jsonflex.String(library),
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got := jsonflex.StringWith(library, jsonflex.WithSummarizeBelow(c.depth))
+			if diff := cmp.Diff(c.expected, got); diff != "" {
+				t.Errorf("mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestStringInterfaceElements(t *testing.T) {
+	got := jsonflex.StringCompact(jsonflex.Array{jsonflex.Number(1), "x", nil, true})
+	if want := `[1, "x", null, true]`; got != want {
+		t.Errorf("expected %s, got %s", want, got)
+	}
+}
+
+func TestStringWithFieldFilters(t *testing.T) {
+	movie := Movie{
+		"id":     jsonflex.Number(1),
+		"title":  "Inception",
+		"adult":  false,
+		"genres": jsonflex.Array{jsonflex.Object{"id": jsonflex.Number(28), "name": "Action"}},
+	}
+	cases := []struct {
+		name     string
+		opts     []jsonflex.StringOption
+		expected string
+	}{
+		{
+			name: "Only",
+			opts: []jsonflex.StringOption{jsonflex.WithOnlyFields("Title", "ID")},
+			expected: `{
+  ID: 1,
+  Title: "Inception",
+}`,
+		},
+		{
+			name: "Only Repeated",
+			opts: []jsonflex.StringOption{jsonflex.WithOnlyFields("Title"), jsonflex.WithOnlyFields("Adult")},
+			expected: `{
+  Adult: false,
+  Title: "Inception",
+}`,
+		},
+		{
+			name: "Exclude Nested",
+			opts: []jsonflex.StringOption{jsonflex.WithExcludeFields("ID", "Adult", "GenreIDs")},
+			expected: `{
+  Genres: [
+    0: {
+      Name: "Action",
+    },
+  ],
+  Title: "Inception",
+}`,
+		},
+		{
+			name:     "Both",
+			opts:     []jsonflex.StringOption{jsonflex.WithOnlyFields("Title"), jsonflex.WithExcludeFields("ID")},
+			expected: "error: WithOnlyFields and WithExcludeFields cannot be combined",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if diff := cmp.Diff(c.expected, jsonflex.StringWith(movie, c.opts...)); diff != "" {
+				t.Errorf("mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
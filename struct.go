@@ -0,0 +1,332 @@
+package jsonflex
+
+import (
+	"fmt"
+	"maps"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// DecodeStruct decodes obj into the struct pointed to by dst.
+//
+// Each exported field is filled from the key named by its `json` struct tag,
+// or from the field name if the tag is absent; a tag of "-" skips the field
+// and tag options such as "omitempty" are ignored. Fields whose key is
+// missing are left untouched, and fields whose value is null are set to
+// their zero value. Field kinds are decoded with the package's converters:
+// numbers must fit the field's type exactly, slices and maps are decoded
+// element by element, nested structs from nested objects, and pointer fields
+// are allocated as needed. Fields of types based on Object or Array, and of
+// interface type, receive the raw value. Fields of embedded structs without
+// a json tag name are promoted as in encoding/json: they are read from keys of
+// obj itself, and fields of the outer struct take precedence on conflicts.
+// Embedded pointers to structs are allocated if nil.
+//
+// Errors for individual fields do not stop decoding; they are collected into
+// an *ErrorList keyed by object key, each message prefixed with its field
+// name.
+func DecodeStruct(obj Object, dst any) error {
+	rv := reflect.ValueOf(dst)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("DecodeStruct: dst must be a non-nil pointer to a struct, got %T", dst)
+	}
+	if obj == nil {
+		return ErrNilObject
+	}
+	return decodeStruct(obj, rv.Elem(), nil)
+}
+
+// DecodeStructWith is like DecodeStruct, but decodes the fields named in
+// overrides, by Go struct field name, with the given Converter instead of by
+// their type, e.g. to parse a date field with AsTimeMulti.
+// The override sees the raw value, including null, and its result must be
+// assignable to the field. Overrides only apply to dst itself, not to nested
+// or embedded structs, and an override naming no exported field of dst is an
+// error.
+func DecodeStructWith(obj Object, dst any, overrides map[string]Converter[any]) error {
+	rv := reflect.ValueOf(dst)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("DecodeStructWith: dst must be a non-nil pointer to a struct, got %T", dst)
+	}
+	for name := range overrides {
+		if field, ok := rv.Elem().Type().FieldByName(name); !ok || !field.IsExported() || len(field.Index) != 1 {
+			return fmt.Errorf("DecodeStructWith: override %q does not name an exported field of %s", name, rv.Elem().Type())
+		}
+	}
+	if obj == nil {
+		return ErrNilObject
+	}
+	return decodeStruct(obj, rv.Elem(), overrides)
+}
+
+// DecodeStructSlice decodes arr, an array of objects, into the slice pointed
+// to by dst, which must be a *[]T for some struct type T (or *T).
+// The slice is allocated to the length of arr and each element is decoded as
+// by DecodeStruct. Errors are collected into an *ErrorList keyed by element
+// index.
+func DecodeStructSlice(arr Array, dst any) error {
+	rv := reflect.ValueOf(dst)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Kind() != reflect.Slice {
+		return fmt.Errorf("DecodeStructSlice: dst must be a non-nil pointer to a slice, got %T", dst)
+	}
+	elem := rv.Elem().Type().Elem()
+	if elem.Kind() == reflect.Pointer {
+		elem = elem.Elem()
+	}
+	if elem.Kind() != reflect.Struct {
+		return fmt.Errorf("DecodeStructSlice: dst must point to a slice of structs, got %T", dst)
+	}
+	if arr == nil {
+		arr = Array{}
+	}
+	return decodeValue(arr, rv.Elem())
+}
+
+// structKey returns the object key for field, or false if it is skipped.
+func structKey(field reflect.StructField) (string, bool) {
+	if !field.IsExported() {
+		return "", false
+	}
+	tag, ok := field.Tag.Lookup("json")
+	if !ok {
+		return field.Name, true
+	}
+	name, _, _ := strings.Cut(tag, ",")
+	if name == "-" {
+		return "", false
+	}
+	if name == "" {
+		return field.Name, true
+	}
+	return name, true
+}
+
+func decodeStruct(obj Object, rv reflect.Value, overrides map[string]Converter[any]) error {
+	var errs ErrorList
+	decodeFields(obj, rv, overrides, "", nil, &errs)
+	return errs.orNil()
+}
+
+// decodeFields decodes the fields of rv into errs. Fields of embedded structs
+// are promoted as in encoding/json, after the fields of rv itself, and skip
+// any key in shadowed, which a shallower field already claimed. Error
+// messages name promoted fields by their path, such as "field Inner.X".
+func decodeFields(obj Object, rv reflect.Value, overrides map[string]Converter[any], prefix string, shadowed map[string]bool, errs *ErrorList) {
+	typ := rv.Type()
+	claimed := maps.Clone(shadowed)
+	if claimed == nil {
+		claimed = make(map[string]bool)
+	}
+	var embedded []int
+	for i := range typ.NumField() {
+		field := typ.Field(i)
+		if isPromoted(field) {
+			embedded = append(embedded, i)
+			continue
+		}
+		key, ok := structKey(field)
+		if !ok || shadowed[key] {
+			continue
+		}
+		claimed[key] = true
+		value, exists := obj[key]
+		if !exists {
+			continue
+		}
+		decode := decodeValue
+		if conv, ok := overrides[field.Name]; ok {
+			decode = func(v any, rv reflect.Value) error {
+				return decodeOverride(conv, v, rv)
+			}
+		}
+		if err := decode(value, rv.Field(i)); err != nil {
+			errs.add(key, fmt.Errorf("field %s%s: %w", prefix, field.Name, err))
+		}
+	}
+	for _, i := range embedded {
+		field := typ.Field(i)
+		fv := rv.Field(i)
+		if field.Type.Kind() == reflect.Pointer {
+			if fv.IsNil() {
+				fv.Set(reflect.New(field.Type.Elem()))
+			}
+			fv = fv.Elem()
+		}
+		decodeFields(obj, fv, nil, prefix+field.Name+".", claimed, errs)
+	}
+}
+
+// isPromoted reports whether field is an embedded struct, or pointer to
+// struct, whose fields are decoded as if they belonged to the outer struct.
+// As in encoding/json, giving the field a json name turns this off, and
+// unexported embedded pointers are skipped since they cannot be allocated.
+func isPromoted(field reflect.StructField) bool {
+	if !field.Anonymous {
+		return false
+	}
+	if name, _, _ := strings.Cut(field.Tag.Get("json"), ","); name != "" {
+		return false
+	}
+	typ := field.Type
+	if typ.Kind() == reflect.Pointer {
+		if !field.IsExported() {
+			return false
+		}
+		typ = typ.Elem()
+	}
+	return typ.Kind() == reflect.Struct
+}
+
+// decodeOverride sets rv to the result of conv.
+func decodeOverride(conv Converter[any], v any, rv reflect.Value) error {
+	result, err := conv(v)
+	if err != nil {
+		return err
+	}
+	if result == nil {
+		rv.SetZero()
+		return nil
+	}
+	value := reflect.ValueOf(result)
+	if !value.Type().AssignableTo(rv.Type()) {
+		ce := newConversionError(result, rv.Type().String())
+		ce.Reason = "override result is not assignable to the field"
+		return ce
+	}
+	rv.Set(value)
+	return nil
+}
+
+func decodeValue(v any, rv reflect.Value) error {
+	if v == nil {
+		rv.SetZero()
+		return nil
+	}
+	typ := rv.Type()
+	switch {
+	case typ.Kind() == reflect.Interface:
+		if !reflect.TypeOf(v).AssignableTo(typ) {
+			return newConversionError(v, typ.String())
+		}
+		rv.Set(reflect.ValueOf(v))
+		return nil
+	case typ.Kind() == reflect.Map && typ.Elem().Kind() == reflect.Interface && typ.ConvertibleTo(reflect.TypeFor[Object]()),
+		typ.Kind() == reflect.Slice && typ.Elem().Kind() == reflect.Interface && typ.ConvertibleTo(reflect.TypeFor[Array]()):
+		raw := reflect.ValueOf(v)
+		if !raw.Type().ConvertibleTo(typ) {
+			return newConversionError(v, typ.String())
+		}
+		rv.Set(raw.Convert(typ))
+		return nil
+	}
+	switch typ.Kind() {
+	case reflect.Bool:
+		b, err := Bool(v)
+		if err != nil {
+			return err
+		}
+		rv.SetBool(b)
+	case reflect.String:
+		s, err := AsString()(v)
+		if err != nil {
+			return err
+		}
+		rv.SetString(s)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, ok := exactInt64(v)
+		if !ok {
+			f, err := Float64(v)
+			if err != nil {
+				return err
+			}
+			if f != math.Trunc(f) || f < math.MinInt64 || f >= math.MaxInt64 {
+				return newConversionError(v, typ.String())
+			}
+			n = int64(f)
+		}
+		if rv.OverflowInt(n) {
+			return newConversionError(v, typ.String())
+		}
+		rv.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, ok := exactUint64(v)
+		if !ok {
+			f, err := Float64(v)
+			if err != nil {
+				return err
+			}
+			if f != math.Trunc(f) || f < 0 || f >= math.MaxUint64 {
+				return newConversionError(v, typ.String())
+			}
+			n = uint64(f)
+		}
+		if rv.OverflowUint(n) {
+			return newConversionError(v, typ.String())
+		}
+		rv.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := Float64(v)
+		if err != nil {
+			return err
+		}
+		if rv.OverflowFloat(f) {
+			return newConversionError(v, typ.String())
+		}
+		rv.SetFloat(f)
+	case reflect.Pointer:
+		elem := reflect.New(typ.Elem())
+		if err := decodeValue(v, elem.Elem()); err != nil {
+			return err
+		}
+		rv.Set(elem)
+	case reflect.Struct:
+		obj, err := AsObject[Object]()(v)
+		if err != nil {
+			return err
+		}
+		return decodeStruct(obj, rv, nil)
+	case reflect.Slice:
+		arr, err := AsArray(AsAny())(v)
+		if err != nil {
+			return err
+		}
+		slice := reflect.MakeSlice(typ, len(arr), len(arr))
+		var errs ErrorList
+		for i, item := range arr {
+			if err := decodeValue(item, slice.Index(i)); err != nil {
+				errs.add(strconv.Itoa(i), fmt.Errorf("item %d: %w", i, err))
+			}
+		}
+		if err := errs.orNil(); err != nil {
+			return err
+		}
+		rv.Set(slice)
+	case reflect.Map:
+		if typ.Key().Kind() != reflect.String {
+			return newConversionError(v, typ.String())
+		}
+		obj, err := AsObject[Object]()(v)
+		if err != nil {
+			return err
+		}
+		m := reflect.MakeMapWithSize(typ, len(obj))
+		var errs ErrorList
+		for key, value := range obj {
+			elem := reflect.New(typ.Elem()).Elem()
+			if err := decodeValue(value, elem); err != nil {
+				errs.add(key, fmt.Errorf("key %q: %w", key, err))
+				continue
+			}
+			m.SetMapIndex(reflect.ValueOf(key).Convert(typ.Key()), elem)
+		}
+		if err := errs.orNil(); err != nil {
+			return err
+		}
+		rv.Set(m)
+	default:
+		return newConversionError(v, typ.String())
+	}
+	return nil
+}
@@ -0,0 +1,223 @@
+package jsonflex_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/krelinga/go-jsonflex"
+)
+
+type movieStruct struct {
+	ID       int32           `json:"id"`
+	Title    string          `json:"title"`
+	Adult    bool            `json:"adult,omitempty"`
+	GenreIDs []uint16        `json:"genre_ids"`
+	Genres   []genreStruct   `json:"genres"`
+	Rating   *float64        `json:"rating"`
+	Extra    jsonflex.Object `json:"extra"`
+	Ratings  map[string]int  `json:"ratings"`
+	Ignored  string          `json:"-"`
+	Tagline  string
+	Raw      any          `json:"raw"`
+	Nested   *genreStruct `json:"nested"`
+	Missing  string       `json:"missing"`
+	skipped  string
+}
+
+type genreStruct struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+func TestDecodeStruct(t *testing.T) {
+	obj := jsonflex.Object{
+		"id":        jsonflex.Number(12345),
+		"title":     "Inception",
+		"adult":     false,
+		"genre_ids": jsonflex.Array{jsonflex.Number(28), jsonflex.Number(12)},
+		"genres":    jsonflex.Array{jsonflex.Object{"id": jsonflex.Number(28), "name": "Action"}},
+		"rating":    jsonflex.Number(8.8),
+		"extra":     jsonflex.Object{"k": "v"},
+		"ratings":   jsonflex.Object{"imdb": jsonflex.Number(9)},
+		"-":         "not a field",
+		"Tagline":   "Your mind is the scene of the crime.",
+		"raw":       jsonflex.Array{"anything"},
+		"nested":    nil,
+	}
+	got := movieStruct{Missing: "kept", Nested: &genreStruct{}}
+	if err := jsonflex.DecodeStruct(obj, &got); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	rating := 8.8
+	expected := movieStruct{
+		ID:       12345,
+		Title:    "Inception",
+		GenreIDs: []uint16{28, 12},
+		Genres:   []genreStruct{{ID: 28, Name: "Action"}},
+		Rating:   &rating,
+		Extra:    jsonflex.Object{"k": "v"},
+		Ratings:  map[string]int{"imdb": 9},
+		Tagline:  "Your mind is the scene of the crime.",
+		Raw:      jsonflex.Array{"anything"},
+		Missing:  "kept",
+	}
+	if diff := cmp.Diff(expected, got, cmp.AllowUnexported(movieStruct{})); diff != "" {
+		t.Errorf("mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestDecodeStructErrors(t *testing.T) {
+	obj := jsonflex.Object{
+		"id":        "not a number",
+		"title":     jsonflex.Number(1),
+		"genre_ids": jsonflex.Array{jsonflex.Number(-1)},
+	}
+	var got movieStruct
+	err := jsonflex.DecodeStruct(obj, &got)
+	if !errors.Is(err, jsonflex.ErrCannotConvert) {
+		t.Fatalf("expected conversion error, got %v", err)
+	}
+	for _, want := range []string{"field ID", "field Title", "field GenreIDs: item 0"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("expected error to mention %q, got %v", want, err)
+		}
+	}
+	var list *jsonflex.ErrorList
+	if !errors.As(err, &list) {
+		t.Fatalf("expected an ErrorList, got %T", err)
+	}
+	byField := list.ByField()
+	for _, key := range []string{"id", "title", "genre_ids"} {
+		if !errors.Is(byField[key], jsonflex.ErrCannotConvert) {
+			t.Errorf("expected conversion error for key %q, got %v", key, byField[key])
+		}
+	}
+
+	if err := jsonflex.DecodeStruct(obj, got); err == nil {
+		t.Error("expected error for non-pointer dst")
+	}
+	if err := jsonflex.DecodeStruct(nil, &got); !errors.Is(err, jsonflex.ErrNilObject) {
+		t.Errorf("expected nil object error, got %v", err)
+	}
+}
+
+func TestDecodeStructWith(t *testing.T) {
+	upper := func(v any) (any, error) {
+		s, err := jsonflex.AsString()(v)
+		return strings.ToUpper(s), err
+	}
+	obj := jsonflex.Object{
+		"id":    jsonflex.Number(7),
+		"title": "inception",
+		"raw":   jsonflex.Number(1),
+	}
+	var got movieStruct
+	err := jsonflex.DecodeStructWith(obj, &got, map[string]jsonflex.Converter[any]{"Title": upper})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.ID != 7 || got.Title != "INCEPTION" || got.Raw != jsonflex.Number(1) {
+		t.Errorf("unexpected result %+v", got)
+	}
+
+	err = jsonflex.DecodeStructWith(jsonflex.Object{"id": jsonflex.Number(7), "title": jsonflex.Number(1)}, &got,
+		map[string]jsonflex.Converter[any]{
+			"Title": upper,
+			"ID": func(any) (any, error) {
+				return "seven", nil
+			},
+		})
+	if !errors.Is(err, jsonflex.ErrCannotConvert) {
+		t.Fatalf("expected conversion error, got %v", err)
+	}
+	for _, want := range []string{"field ID", "not assignable", "field Title"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("expected error to mention %q, got %v", want, err)
+		}
+	}
+
+	if err := jsonflex.DecodeStructWith(obj, &got, map[string]jsonflex.Converter[any]{"skipped": upper}); err == nil {
+		t.Error("expected error for override naming an unexported field")
+	}
+	var embedding embeddingStruct
+	if err := jsonflex.DecodeStructWith(obj, &embedding, map[string]jsonflex.Converter[any]{"Y": jsonflex.AsAny()}); err == nil {
+		t.Error("expected error for override naming a promoted field")
+	}
+	if err := jsonflex.DecodeStructWith(nil, &got, nil); !errors.Is(err, jsonflex.ErrNilObject) {
+		t.Errorf("expected nil object error, got %v", err)
+	}
+}
+
+type embeddedBase struct {
+	X      int    `json:"X"`
+	Shared string `json:"shared"`
+}
+
+type EmbeddedExtra struct {
+	Y int `json:"y"`
+}
+
+type embeddingStruct struct {
+	embeddedBase
+	*EmbeddedExtra
+	Named  embeddedBase `json:"named"`
+	Shared string       `json:"shared"`
+}
+
+func TestDecodeStructEmbedded(t *testing.T) {
+	obj := jsonflex.Object{
+		"X":      jsonflex.Number(1),
+		"y":      jsonflex.Number(2),
+		"shared": "outer",
+		"named":  jsonflex.Object{"X": jsonflex.Number(3)},
+	}
+	var got embeddingStruct
+	if err := jsonflex.DecodeStruct(obj, &got); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := embeddingStruct{
+		embeddedBase:  embeddedBase{X: 1},
+		EmbeddedExtra: &EmbeddedExtra{Y: 2},
+		Named:         embeddedBase{X: 3},
+		Shared:        "outer",
+	}
+	if diff := cmp.Diff(expected, got, cmp.AllowUnexported(embeddingStruct{})); diff != "" {
+		t.Errorf("mismatch (-want +got):\n%s", diff)
+	}
+
+	err := jsonflex.DecodeStruct(jsonflex.Object{"X": "one"}, &got)
+	if !errors.Is(err, jsonflex.ErrCannotConvert) || !strings.Contains(err.Error(), "field embeddedBase.X") {
+		t.Errorf("expected conversion error naming embeddedBase.X, got %v", err)
+	}
+}
+
+func TestDecodeStructSlice(t *testing.T) {
+	arr := jsonflex.Array{
+		jsonflex.Object{"id": jsonflex.Number(28), "name": "Action"},
+		jsonflex.Object{"id": jsonflex.Number(12), "name": "Adventure"},
+	}
+	var got []genreStruct
+	if err := jsonflex.DecodeStructSlice(arr, &got); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if diff := cmp.Diff([]genreStruct{{ID: 28, Name: "Action"}, {ID: 12, Name: "Adventure"}}, got); diff != "" {
+		t.Errorf("mismatch (-want +got):\n%s", diff)
+	}
+
+	var ptrs []*genreStruct
+	if err := jsonflex.DecodeStructSlice(arr, &ptrs); err != nil || len(ptrs) != 2 || ptrs[1].Name != "Adventure" {
+		t.Errorf("expected two decoded pointers, got %v with error %v", ptrs, err)
+	}
+
+	arr = append(arr, "not an object")
+	err := jsonflex.DecodeStructSlice(arr, &got)
+	if !errors.Is(err, jsonflex.ErrCannotConvert) || !strings.Contains(err.Error(), "item 2") {
+		t.Errorf("expected conversion error naming item 2, got %v", err)
+	}
+	var notStructs []string
+	if err := jsonflex.DecodeStructSlice(arr, &notStructs); err == nil {
+		t.Error("expected error for slice of non-structs")
+	}
+}
@@ -0,0 +1,89 @@
+package jsonflex
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+)
+
+// AsTimeMulti returns a Converter that converts a string to time.Time by
+// trying each of layouts in order, as accepted by time.Parse.
+// The first layout that parses successfully wins. If none do, the error wraps
+// ErrCannotConvert and joins the parse error of every attempted layout.
+func AsTimeMulti(layouts ...string) Converter[time.Time] {
+	return func(v any) (time.Time, error) {
+		s, err := AsString()(v)
+		if err != nil {
+			return time.Time{}, err
+		}
+		errs := make([]error, 0, len(layouts))
+		for _, layout := range layouts {
+			t, err := time.Parse(layout, s)
+			if err == nil {
+				return t, nil
+			}
+			errs = append(errs, fmt.Errorf("layout %q: %w", layout, err))
+		}
+		ce := newConversionError(v, "time.Time")
+		ce.Reason = fmt.Sprintf("%q matches none of %d layouts", s, len(layouts))
+		return time.Time{}, errors.Join(append([]error{ce}, errs...)...)
+	}
+}
+
+// AsUnixTime returns a Converter that converts a number of seconds since the
+// Unix epoch, which may have a fractional part, into a time.Time in UTC.
+// NaN and infinite values are rejected.
+func AsUnixTime() Converter[time.Time] {
+	return func(v any) (time.Time, error) {
+		f, err := Float64(v)
+		if err != nil {
+			return time.Time{}, err
+		}
+		if math.IsNaN(f) || math.IsInf(f, 0) {
+			ce := newConversionError(v, "time.Time")
+			ce.Reason = "value is not finite"
+			return time.Time{}, ce
+		}
+		sec, frac := math.Modf(f)
+		return time.Unix(int64(sec), int64(frac*1e9)).UTC(), nil
+	}
+}
+
+// TimePoint is a single sample of a time series.
+type TimePoint struct {
+	Time  time.Time
+	Value float64
+}
+
+// AsTimeSeries returns a Converter for metrics in the common
+// [[timestamp, value], ...] format, where each timestamp is in Unix seconds
+// as accepted by AsUnixTime. Every element must be a 2-element array;
+// errors name the index of the offending element.
+func AsTimeSeries() Converter[[]TimePoint] {
+	return asTimeSeries(AsUnixTime())
+}
+
+// AsTimeSeriesRFC3339 is like AsTimeSeries, but expects each timestamp to be
+// an RFC 3339 string.
+func AsTimeSeriesRFC3339() Converter[[]TimePoint] {
+	return asTimeSeries(AsTimeMulti(time.RFC3339Nano))
+}
+
+func asTimeSeries(timeConv Converter[time.Time]) Converter[[]TimePoint] {
+	return AsArray(func(v any) (TimePoint, error) {
+		pair, err := tupleElements(v, 2)
+		if err != nil {
+			return TimePoint{}, err
+		}
+		t, err := timeConv(pair[0])
+		if err != nil {
+			return TimePoint{}, fmt.Errorf("timestamp: %w", err)
+		}
+		value, err := Float64(pair[1])
+		if err != nil {
+			return TimePoint{}, fmt.Errorf("value: %w", err)
+		}
+		return TimePoint{Time: t, Value: value}, nil
+	})
+}
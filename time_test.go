@@ -0,0 +1,93 @@
+package jsonflex_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/krelinga/go-jsonflex"
+)
+
+func TestAsTimeMulti(t *testing.T) {
+	conv := jsonflex.AsTimeMulti(time.RFC3339, time.DateOnly, "02 Jan 2006 15:04")
+
+	got, err := conv("2010-07-16T10:00:00Z")
+	if err != nil || !got.Equal(time.Date(2010, 7, 16, 10, 0, 0, 0, time.UTC)) {
+		t.Errorf("expected RFC3339 time, got %v with error %v", got, err)
+	}
+
+	got, err = conv("16 Jul 2010 10:30")
+	if err != nil || !got.Equal(time.Date(2010, 7, 16, 10, 30, 0, 0, time.UTC)) {
+		t.Errorf("expected time parsed by third layout, got %v with error %v", got, err)
+	}
+
+	_, err = conv("yesterday")
+	if !errors.Is(err, jsonflex.ErrCannotConvert) || !strings.Contains(err.Error(), `layout "2006-01-02"`) {
+		t.Errorf("expected conversion error listing layouts, got %v", err)
+	}
+	if _, err := conv(nil); !errors.Is(err, jsonflex.ErrNullValue) {
+		t.Errorf("expected null value error, got %v", err)
+	}
+}
+
+func TestAsUnixTime(t *testing.T) {
+	got, err := jsonflex.AsUnixTime()(jsonflex.Number(1279274400.5))
+	want := time.Date(2010, 7, 16, 10, 0, 0, 500_000_000, time.UTC)
+	if err != nil || !got.Equal(want) || got.Location() != time.UTC {
+		t.Errorf("expected %v, got %v with error %v", want, got, err)
+	}
+	if _, err := jsonflex.AsUnixTime()("1279274400"); !errors.Is(err, jsonflex.ErrCannotConvert) {
+		t.Errorf("expected conversion error, got %v", err)
+	}
+}
+
+func TestAsTimeSeries(t *testing.T) {
+	got, err := jsonflex.AsTimeSeries()(jsonflex.Array{
+		jsonflex.Array{jsonflex.Number(0), jsonflex.Number(1.5)},
+		jsonflex.Array{jsonflex.Number(60), jsonflex.Number(2)},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []jsonflex.TimePoint{
+		{Time: time.Unix(0, 0).UTC(), Value: 1.5},
+		{Time: time.Unix(60, 0).UTC(), Value: 2},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("expected %d points, got %d", len(want), len(got))
+	}
+	for i := range want {
+		if !got[i].Time.Equal(want[i].Time) || got[i].Value != want[i].Value {
+			t.Errorf("point %d: expected %v, got %v", i, want[i], got[i])
+		}
+	}
+
+	_, err = jsonflex.AsTimeSeries()(jsonflex.Array{
+		jsonflex.Array{jsonflex.Number(0), jsonflex.Number(1)},
+		jsonflex.Array{jsonflex.Number(60)},
+	})
+	if !errors.Is(err, jsonflex.ErrCannotConvert) || !strings.Contains(err.Error(), "item 1") {
+		t.Errorf("expected arity error naming item 1, got %v", err)
+	}
+	_, err = jsonflex.AsTimeSeries()(jsonflex.Array{jsonflex.Array{jsonflex.Number(0), "high"}})
+	if !errors.Is(err, jsonflex.ErrCannotConvert) || !strings.Contains(err.Error(), "item 0: value: ") {
+		t.Errorf("expected value error naming item 0, got %v", err)
+	}
+}
+
+func TestAsTimeSeriesRFC3339(t *testing.T) {
+	got, err := jsonflex.AsTimeSeriesRFC3339()(jsonflex.Array{
+		jsonflex.Array{"2010-07-16T10:00:00Z", jsonflex.Number(3)},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got) != 1 || !got[0].Time.Equal(time.Date(2010, 7, 16, 10, 0, 0, 0, time.UTC)) || got[0].Value != 3 {
+		t.Errorf("unexpected series: %v", got)
+	}
+	_, err = jsonflex.AsTimeSeriesRFC3339()(jsonflex.Array{jsonflex.Array{jsonflex.Number(0), jsonflex.Number(3)}})
+	if !errors.Is(err, jsonflex.ErrCannotConvert) || !strings.Contains(err.Error(), "item 0: timestamp: ") {
+		t.Errorf("expected timestamp error naming item 0, got %v", err)
+	}
+}
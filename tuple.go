@@ -0,0 +1,99 @@
+package jsonflex
+
+import "fmt"
+
+// tupleElements returns the elements of v, which must be an array of exactly
+// n elements.
+func tupleElements(v any, n int) ([]any, error) {
+	arr, err := rawArray(v)
+	if err != nil {
+		return nil, err
+	}
+	if len(arr) != n {
+		ce := newConversionError(v, fmt.Sprintf("%d-element array", n))
+		ce.Reason = fmt.Sprintf("got %d elements", len(arr))
+		return nil, ce
+	}
+	return arr, nil
+}
+
+// AsCSVRecord returns a Converter that renders a fixed-arity array as a
+// []string suitable for csv.Writer.Write, converting the element at each
+// position with the Converter at the same position in convs.
+// The array must have exactly len(convs) elements. Use AsStringCoerce for
+// columns whose source values are not strings.
+func AsCSVRecord(convs ...Converter[string]) Converter[[]string] {
+	return func(v any) ([]string, error) {
+		arr, err := tupleElements(v, len(convs))
+		if err != nil {
+			return nil, err
+		}
+		record := make([]string, len(convs))
+		for i, conv := range convs {
+			field, err := conv(arr[i])
+			if err != nil {
+				return nil, itemError(i, arr[i], err)
+			}
+			record[i] = field
+		}
+		return record, nil
+	}
+}
+
+// Coordinate is a GeoJSON position.
+type Coordinate struct {
+	Lon, Lat float64
+	// Alt is the altitude, or nil if the position has only two elements.
+	Alt *float64
+}
+
+// AsCoordinate returns a Converter that converts a GeoJSON position, a
+// [lon, lat] or [lon, lat, alt] numeric array, into a Coordinate.
+// Arrays of any other length are rejected with ErrCannotConvert. The values
+// are not range checked; use AsCoordinateStrict for that.
+func AsCoordinate() Converter[Coordinate] {
+	return func(v any) (Coordinate, error) {
+		arr, err := rawArray(v)
+		if err != nil {
+			return Coordinate{}, err
+		}
+		if len(arr) != 2 && len(arr) != 3 {
+			ce := newConversionError(v, "Coordinate")
+			ce.Reason = fmt.Sprintf("got %d elements, expected 2 or 3", len(arr))
+			return Coordinate{}, ce
+		}
+		values, err := AsArray(AsFloat64())(arr)
+		if err != nil {
+			return Coordinate{}, err
+		}
+		c := Coordinate{Lon: values[0], Lat: values[1]}
+		if len(values) == 3 {
+			c.Alt = &values[2]
+		}
+		return c, nil
+	}
+}
+
+// AsCoordinateStrict is like AsCoordinate, but also rejects longitudes
+// outside [-180, 180] and latitudes outside [-90, 90].
+func AsCoordinateStrict() Converter[Coordinate] {
+	return func(v any) (Coordinate, error) {
+		c, err := AsCoordinate()(v)
+		if err != nil {
+			return Coordinate{}, err
+		}
+		reason := ""
+		switch {
+		case c.Lon < -180 || c.Lon > 180:
+			reason = fmt.Sprintf("longitude %v out of range", c.Lon)
+		case c.Lat < -90 || c.Lat > 90:
+			reason = fmt.Sprintf("latitude %v out of range", c.Lat)
+		}
+		if reason != "" {
+			ce := newConversionError(v, "Coordinate")
+			ce.Reason = reason
+			return Coordinate{}, ce
+		}
+		return c, nil
+	}
+}
@@ -0,0 +1,82 @@
+package jsonflex_test
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/krelinga/go-jsonflex"
+)
+
+func TestAsCSVRecord(t *testing.T) {
+	conv := jsonflex.AsCSVRecord(jsonflex.AsString(), jsonflex.AsStringCoerce(), jsonflex.AsStringCoerce())
+	got, err := conv(jsonflex.Array{"Inception, the movie", jsonflex.Number(2010), true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if diff := cmp.Diff([]string{"Inception, the movie", "2010", "true"}, got); diff != "" {
+		t.Errorf("mismatch (-want +got):\n%s", diff)
+	}
+
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	if err := w.Write(got); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	w.Flush()
+	if want := "\"Inception, the movie\",2010,true\n"; buf.String() != want {
+		t.Errorf("expected %q, got %q", want, buf.String())
+	}
+
+	if _, err := conv(jsonflex.Array{"a", "b"}); !errors.Is(err, jsonflex.ErrCannotConvert) || !strings.Contains(err.Error(), "got 2 elements") {
+		t.Errorf("expected arity error, got %v", err)
+	}
+	if _, err := conv(jsonflex.Array{jsonflex.Number(1), "b", "c"}); !errors.Is(err, jsonflex.ErrCannotConvert) || !strings.Contains(err.Error(), "item 0") {
+		t.Errorf("expected error naming item 0, got %v", err)
+	}
+}
+
+func TestAsCoordinate(t *testing.T) {
+	alt := 12.5
+	cases := []struct {
+		name     string
+		input    jsonflex.Array
+		expected jsonflex.Coordinate
+	}{
+		{name: "2D", input: jsonflex.Array{jsonflex.Number(-122.4), jsonflex.Number(37.8)}, expected: jsonflex.Coordinate{Lon: -122.4, Lat: 37.8}},
+		{name: "3D", input: jsonflex.Array{jsonflex.Number(2.35), jsonflex.Number(48.85), jsonflex.Number(12.5)}, expected: jsonflex.Coordinate{Lon: 2.35, Lat: 48.85, Alt: &alt}},
+		{name: "out of range", input: jsonflex.Array{jsonflex.Number(200), jsonflex.Number(100)}, expected: jsonflex.Coordinate{Lon: 200, Lat: 100}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got, err := jsonflex.AsCoordinate()(c.input)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(c.expected, got); diff != "" {
+				t.Errorf("mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+
+	for _, bad := range []any{jsonflex.Array{jsonflex.Number(1)}, jsonflex.Array{jsonflex.Number(1), jsonflex.Number(2), jsonflex.Number(3), jsonflex.Number(4)}, jsonflex.Array{"1", "2"}} {
+		if _, err := jsonflex.AsCoordinate()(bad); !errors.Is(err, jsonflex.ErrCannotConvert) {
+			t.Errorf("%v: expected conversion error, got %v", bad, err)
+		}
+	}
+
+	if _, err := jsonflex.AsCoordinateStrict()(jsonflex.Array{jsonflex.Number(180), jsonflex.Number(-90)}); err != nil {
+		t.Errorf("unexpected error at range boundary: %v", err)
+	}
+	_, err := jsonflex.AsCoordinateStrict()(jsonflex.Array{jsonflex.Number(10), jsonflex.Number(91)})
+	if !errors.Is(err, jsonflex.ErrCannotConvert) || !strings.Contains(err.Error(), "latitude 91 out of range") {
+		t.Errorf("expected latitude range error, got %v", err)
+	}
+	_, err = jsonflex.AsCoordinateStrict()(jsonflex.Array{jsonflex.Number(-181), jsonflex.Number(0)})
+	if !errors.Is(err, jsonflex.ErrCannotConvert) || !strings.Contains(err.Error(), "longitude -181 out of range") {
+		t.Errorf("expected longitude range error, got %v", err)
+	}
+}
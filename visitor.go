@@ -0,0 +1,81 @@
+package jsonflex
+
+import "reflect"
+
+// Visitor receives the events of a traversal by WalkValue. Implementations
+// can render values in formats of their own, such as YAML, without needing
+// an option on String for every variation.
+type Visitor interface {
+	// EnterObject is called when an object v is reached, that is, a value of
+	// a map type such as one based on Object. If it returns false, the
+	// object's fields are skipped and LeaveObject is not called.
+	EnterObject(v any) bool
+	// ObjectField is called for each accessor method of the current object,
+	// in alphabetical order by method name. If the accessor succeeded, err is
+	// nil and returning true visits the returned value next. Otherwise err
+	// is the accessor's error, which wraps ErrNullValue for null fields and
+	// ErrFieldNotFound for absent ones, and no value is visited.
+	ObjectField(name string, err error) bool
+	// LeaveObject is called after the last field of an object v.
+	LeaveObject(v any)
+	// EnterArray is called when a slice v is reached. If it returns false,
+	// the elements are skipped and LeaveArray is not called.
+	EnterArray(v any) bool
+	// Element is called before the element at index i of the current array
+	// is visited.
+	Element(i int)
+	// LeaveArray is called after the last element of an array v.
+	LeaveArray(v any)
+	// Scalar is called for every other value, including nil.
+	Scalar(v any)
+}
+
+// WalkValue traverses v, reporting what it finds to vis.
+//
+// Values of map kind, including all types rooted in Object, are objects whose
+// fields are the accessor methods that take no arguments and return a value
+// and an error, as rendered by String. Values of slice kind are arrays, and
+// everything else, including nil, is a scalar. Elements held in interfaces,
+// as in an Array, are visited as their dynamic value.
+func WalkValue(v any, vis Visitor) {
+	walk(reflect.ValueOf(v), vis)
+}
+
+func walk(v reflect.Value, vis Visitor) {
+	if v.Kind() == reflect.Interface {
+		v = v.Elem()
+	}
+	if !v.IsValid() {
+		vis.Scalar(nil)
+		return
+	}
+	switch v.Kind() {
+	case reflect.Map:
+		if !vis.EnterObject(v.Interface()) {
+			return
+		}
+		for i := range v.Type().NumMethod() {
+			method := v.Type().Method(i)
+			if method.Type.NumIn() != 1 || method.Type.NumOut() != 2 || method.Type.Out(1) != reflect.TypeFor[error]() {
+				continue
+			}
+			outs := method.Func.Call([]reflect.Value{v})
+			err, _ := outs[1].Interface().(error)
+			if vis.ObjectField(method.Name, err) && err == nil {
+				walk(outs[0], vis)
+			}
+		}
+		vis.LeaveObject(v.Interface())
+	case reflect.Slice:
+		if !vis.EnterArray(v.Interface()) {
+			return
+		}
+		for i := range v.Len() {
+			vis.Element(i)
+			walk(v.Index(i), vis)
+		}
+		vis.LeaveArray(v.Interface())
+	default:
+		vis.Scalar(v.Interface())
+	}
+}
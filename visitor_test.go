@@ -0,0 +1,110 @@
+package jsonflex_test
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/krelinga/go-jsonflex"
+)
+
+// eventVisitor records the events of a walk, skipping null fields and
+// objects of type Genre.
+type eventVisitor struct {
+	events []string
+}
+
+func (e *eventVisitor) EnterObject(v any) bool {
+	if _, ok := v.(Genre); ok {
+		e.events = append(e.events, "skip genre")
+		return false
+	}
+	e.events = append(e.events, "enter object")
+	return true
+}
+
+func (e *eventVisitor) ObjectField(name string, err error) bool {
+	switch {
+	case err == nil:
+		e.events = append(e.events, "field "+name)
+	case errors.Is(err, jsonflex.ErrNullValue):
+		e.events = append(e.events, "null "+name)
+	case errors.Is(err, jsonflex.ErrFieldNotFound):
+		e.events = append(e.events, "absent "+name)
+	default:
+		e.events = append(e.events, "error "+name)
+	}
+	return true
+}
+
+func (e *eventVisitor) LeaveObject(any) { e.events = append(e.events, "leave object") }
+
+func (e *eventVisitor) EnterArray(v any) bool {
+	e.events = append(e.events, "enter array")
+	return true
+}
+
+func (e *eventVisitor) Element(i int) { e.events = append(e.events, fmt.Sprintf("element %d", i)) }
+
+func (e *eventVisitor) LeaveArray(any) { e.events = append(e.events, "leave array") }
+
+func (e *eventVisitor) Scalar(v any) { e.events = append(e.events, fmt.Sprintf("scalar %v", v)) }
+
+func TestWalkValue(t *testing.T) {
+	movie := Movie{
+		"title":     nil,
+		"id":        "not a number",
+		"genre_ids": jsonflex.Array{jsonflex.Number(1)},
+		"genres":    jsonflex.Array{jsonflex.Object{"id": jsonflex.Number(28)}},
+	}
+	var vis eventVisitor
+	jsonflex.WalkValue(movie, &vis)
+	expected := []string{
+		"enter object",
+		"absent Adult",
+		"field GenreIDs", "enter array", "element 0", "scalar 1", "leave array",
+		"field Genres", "enter array", "element 0", "skip genre", "leave array",
+		"error ID",
+		"null Title",
+		"leave object",
+	}
+	if diff := cmp.Diff(expected, vis.events); diff != "" {
+		t.Errorf("mismatch (-want +got):\n%s", diff)
+	}
+
+	vis = eventVisitor{}
+	jsonflex.WalkValue(jsonflex.Array{"x", nil}, &vis)
+	expected = []string{"enter array", "element 0", "scalar x", "element 1", "scalar <nil>", "leave array"}
+	if diff := cmp.Diff(expected, vis.events); diff != "" {
+		t.Errorf("mismatch (-want +got):\n%s", diff)
+	}
+}
+
+// keysVisitor renders only the field names of objects, as a custom format
+// built on WalkValue.
+type keysVisitor struct {
+	sb strings.Builder
+}
+
+func (k *keysVisitor) EnterObject(any) bool { k.sb.WriteString("("); return true }
+
+func (k *keysVisitor) ObjectField(name string, err error) bool {
+	k.sb.WriteString(name + " ")
+	return err == nil
+}
+
+func (k *keysVisitor) LeaveObject(any)     { k.sb.WriteString(")") }
+func (k *keysVisitor) EnterArray(any) bool { return true }
+func (k *keysVisitor) Element(int)         {}
+func (k *keysVisitor) LeaveArray(any)      {}
+func (k *keysVisitor) Scalar(any)          {}
+
+func TestWalkValueCustomFormat(t *testing.T) {
+	var vis keysVisitor
+	jsonflex.WalkValue(Movie{"title": "x", "genres": jsonflex.Array{jsonflex.Object{"id": jsonflex.Number(1)}}}, &vis)
+	if want := "(Adult GenreIDs Genres (ID Name )ID Title )"; vis.sb.String() != want {
+		t.Errorf("expected %q, got %q", want, vis.sb.String())
+	}
+}